@@ -0,0 +1,137 @@
+// SquirrelDB Go SDK - Collection Management
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CollectionOptions configures a collection created with CreateCollection
+type CollectionOptions struct {
+	// TTL expires documents this long after their last update, counted in
+	// whole seconds; 0 keeps them
+	TTL time.Duration
+	// MaxDocuments caps the collection, evicting the oldest documents; 0 is unlimited
+	MaxDocuments int64
+	// MaxSize caps the storage size in bytes, evicting the oldest documents; 0 is unlimited
+	MaxSize int64
+}
+
+type wireCollectionOptions struct {
+	TTLSeconds   int64 `json:"ttl_seconds,omitempty"`
+	MaxDocuments int64 `json:"max_documents,omitempty"`
+	MaxSize      int64 `json:"max_size,omitempty"`
+}
+
+func (o *CollectionOptions) validate() error {
+	if o != nil && (o.TTL < 0 || (o.TTL > 0 && o.TTL < time.Second)) {
+		return fmt.Errorf("collection options: TTL %v must be 0 or at least one second", o.TTL)
+	}
+	return nil
+}
+
+func (o *CollectionOptions) wire() wireCollectionOptions {
+	if o == nil {
+		return wireCollectionOptions{}
+	}
+	return wireCollectionOptions{
+		TTLSeconds:   int64(o.TTL / time.Second),
+		MaxDocuments: o.MaxDocuments,
+		MaxSize:      o.MaxSize,
+	}
+}
+
+func (w wireCollectionOptions) options() CollectionOptions {
+	return CollectionOptions{
+		TTL:          time.Duration(w.TTLSeconds) * time.Second,
+		MaxDocuments: w.MaxDocuments,
+		MaxSize:      w.MaxSize,
+	}
+}
+
+// CollectionStats describes the size of a collection
+type CollectionStats struct {
+	Name string
+	// Count is the number of documents
+	Count int64
+	// Size is the storage size of the documents in bytes
+	Size int64
+	// IndexSize is the storage size of the indexes in bytes
+	IndexSize int64
+	Options   CollectionOptions
+}
+
+// admin sends a schema-level request. The idempotency key keeps a retried
+// request from failing because its first attempt already applied.
+func (c *Client) admin(ctx context.Context, msg map[string]interface{}) (json.RawMessage, error) {
+	msg["idempotency_key"] = newIdempotencyKey()
+	return c.request(ctx, msg)
+}
+
+// CreateCollection creates an empty collection; it fails with an error
+// matching ErrConflict if the collection already exists
+func (c *Client) CreateCollection(ctx context.Context, name string, opts *CollectionOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	_, err := c.admin(ctx, map[string]interface{}{
+		"type":       "CreateCollection",
+		"collection": name,
+		"options":    opts.wire(),
+	})
+	return err
+}
+
+// DropCollection deletes a collection with all its documents and indexes;
+// it fails with an error matching ErrNotFound if the collection does not exist
+func (c *Client) DropCollection(ctx context.Context, name string) error {
+	_, err := c.admin(ctx, map[string]interface{}{
+		"type":       "DropCollection",
+		"collection": name,
+	})
+	return err
+}
+
+// RenameCollection renames a collection; it fails with an error matching
+// ErrConflict if the new name is taken
+func (c *Client) RenameCollection(ctx context.Context, from, to string) error {
+	_, err := c.admin(ctx, map[string]interface{}{
+		"type":       "RenameCollection",
+		"collection": from,
+		"new_name":   to,
+	})
+	return err
+}
+
+// CollectionStats returns the size and options of a collection
+func (c *Client) CollectionStats(ctx context.Context, name string) (*CollectionStats, error) {
+	result, err := c.request(ctx, map[string]interface{}{
+		"type":       "CollectionStats",
+		"collection": name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Stats struct {
+			Name      string                `json:"name"`
+			Count     int64                 `json:"count"`
+			Size      int64                 `json:"size"`
+			IndexSize int64                 `json:"index_size"`
+			Options   wireCollectionOptions `json:"options"`
+		} `json:"stats"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, err
+	}
+	return &CollectionStats{
+		Name:      resp.Stats.Name,
+		Count:     resp.Stats.Count,
+		Size:      resp.Stats.Size,
+		IndexSize: resp.Stats.IndexSize,
+		Options:   resp.Stats.Options.options(),
+	}, nil
+}
@@ -0,0 +1,132 @@
+// SquirrelDB Go SDK - Collection Management Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// serveCatalog answers collection management requests from an in-memory catalog
+func serveCatalog(srv *fakeServer) {
+	var mu sync.Mutex
+	catalog := map[string]map[string]interface{}{}
+	errReply := func(code, message string) map[string]interface{} {
+		return map[string]interface{}{"type": "Error", "code": code, "message": message}
+	}
+	srv.handle("CreateCollection", func(msg map[string]interface{}) map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		name := msg["collection"].(string)
+		if _, ok := catalog[name]; ok {
+			return errReply(CodeConflict, name+" exists")
+		}
+		catalog[name], _ = msg["options"].(map[string]interface{})
+		return map[string]interface{}{"type": "Result"}
+	})
+	srv.handle("DropCollection", func(msg map[string]interface{}) map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		name := msg["collection"].(string)
+		if _, ok := catalog[name]; !ok {
+			return errReply(CodeNotFound, name+" not found")
+		}
+		delete(catalog, name)
+		return map[string]interface{}{"type": "Result"}
+	})
+	srv.handle("RenameCollection", func(msg map[string]interface{}) map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		from, to := msg["collection"].(string), msg["new_name"].(string)
+		opts, ok := catalog[from]
+		if !ok {
+			return errReply(CodeNotFound, from+" not found")
+		}
+		if _, taken := catalog[to]; taken {
+			return errReply(CodeConflict, to+" exists")
+		}
+		delete(catalog, from)
+		catalog[to] = opts
+		return map[string]interface{}{"type": "Result"}
+	})
+	srv.handle("CollectionStats", func(msg map[string]interface{}) map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		name := msg["collection"].(string)
+		opts, ok := catalog[name]
+		if !ok {
+			return errReply(CodeNotFound, name+" not found")
+		}
+		return map[string]interface{}{"type": "Result", "stats": map[string]interface{}{
+			"name": name, "count": 42, "size": 4096, "index_size": 512, "options": opts,
+		}}
+	})
+	srv.handle("ListCollections", func(msg map[string]interface{}) map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		names := []string{}
+		for name := range catalog {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return map[string]interface{}{"type": "Collections", "collections": names}
+	})
+}
+
+func TestCollectionLifecycle(t *testing.T) {
+	srv := newFakeServer(t)
+	serveCatalog(srv)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	opts := &CollectionOptions{TTL: time.Hour, MaxDocuments: 1000}
+	if err := client.CreateCollection(ctx, "sessions", opts); err != nil {
+		t.Fatalf("CreateCollection failed: %v", err)
+	}
+	if err := client.CreateCollection(ctx, "sessions", nil); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected conflict creating twice, got %v", err)
+	}
+	if err := client.CreateCollection(ctx, "ephemeral", &CollectionOptions{TTL: time.Millisecond}); err == nil {
+		t.Error("Expected sub-second TTL to be rejected")
+	}
+	msg := srv.messages("CreateCollection")[0]
+	if wire := msg["options"].(map[string]interface{}); wire["ttl_seconds"] != float64(3600) || wire["max_documents"] != float64(1000) {
+		t.Errorf("Unexpected wire options: %v", wire)
+	}
+	if msg["idempotency_key"] == nil {
+		t.Error("Expected idempotency key on CreateCollection")
+	}
+
+	if err := client.RenameCollection(ctx, "sessions", "web_sessions"); err != nil {
+		t.Fatalf("RenameCollection failed: %v", err)
+	}
+	names, err := client.ListCollections(ctx)
+	if err != nil || len(names) != 1 || names[0] != "web_sessions" {
+		t.Errorf("Unexpected collections: %v, %v", names, err)
+	}
+
+	stats, err := client.CollectionStats(ctx, "web_sessions")
+	if err != nil {
+		t.Fatalf("CollectionStats failed: %v", err)
+	}
+	if stats.Name != "web_sessions" || stats.Count != 42 || stats.Size != 4096 || stats.IndexSize != 512 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.Options != *opts {
+		t.Errorf("Expected options %+v, got %+v", *opts, stats.Options)
+	}
+
+	if err := client.DropCollection(ctx, "web_sessions"); err != nil {
+		t.Fatalf("DropCollection failed: %v", err)
+	}
+	if err := client.DropCollection(ctx, "web_sessions"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected not found dropping twice, got %v", err)
+	}
+	if _, err := client.CollectionStats(ctx, "web_sessions"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected not found for dropped collection, got %v", err)
+	}
+}
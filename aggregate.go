@@ -0,0 +1,226 @@
+// SquirrelDB Go SDK - Aggregation
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// AggregateOp is an aggregation function
+type AggregateOp string
+
+const (
+	AggCount         AggregateOp = "count"
+	AggSum           AggregateOp = "sum"
+	AggAvg           AggregateOp = "avg"
+	AggMin           AggregateOp = "min"
+	AggMax           AggregateOp = "max"
+	AggCountDistinct AggregateOp = "count_distinct"
+)
+
+// AggregateField is a computed value of an aggregation, stored under Name
+type AggregateField struct {
+	Name  string      `json:"name"`
+	Op    AggregateOp `json:"op"`
+	Field string      `json:"field,omitempty"`
+}
+
+// StructuredAggregate represents the wire format aggregation. Query selects
+// the input documents; its sort, skip and limit apply to the output rows.
+type StructuredAggregate struct {
+	Query   StructuredQuery  `json:"query"`
+	GroupBy []string         `json:"group_by,omitempty"`
+	Fields  []AggregateField `json:"fields"`
+}
+
+// AggregateBuilder builds an aggregation over the documents matching a query
+type AggregateBuilder struct {
+	query   *QueryBuilder
+	groupBy []string
+	fields  []AggregateField
+}
+
+// Aggregate starts an aggregation over the documents matching q. Sort,
+// skip and limit set on q apply to the output rows, and may refer to group
+// fields or aggregate names.
+func Aggregate(q *QueryBuilder) *AggregateBuilder {
+	a := &AggregateBuilder{}
+	if q != nil {
+		a.query = q.clone()
+	}
+	return a
+}
+
+// GroupBy computes one row per distinct combination of field values;
+// without it the aggregation yields a single row
+func (a *AggregateBuilder) GroupBy(fields ...string) *AggregateBuilder {
+	a.groupBy = append(a.groupBy, fields...)
+	return a
+}
+
+// Count the documents, stored under name
+func (a *AggregateBuilder) Count(name string) *AggregateBuilder {
+	return a.add(name, AggCount, "")
+}
+
+// Sum a numeric field, stored under name
+func (a *AggregateBuilder) Sum(field, name string) *AggregateBuilder {
+	return a.add(name, AggSum, field)
+}
+
+// Avg averages a numeric field, stored under name
+func (a *AggregateBuilder) Avg(field, name string) *AggregateBuilder {
+	return a.add(name, AggAvg, field)
+}
+
+// Min of a field, stored under name
+func (a *AggregateBuilder) Min(field, name string) *AggregateBuilder {
+	return a.add(name, AggMin, field)
+}
+
+// Max of a field, stored under name
+func (a *AggregateBuilder) Max(field, name string) *AggregateBuilder {
+	return a.add(name, AggMax, field)
+}
+
+// CountDistinct counts the distinct values of a field, stored under name
+func (a *AggregateBuilder) CountDistinct(field, name string) *AggregateBuilder {
+	return a.add(name, AggCountDistinct, field)
+}
+
+func (a *AggregateBuilder) add(name string, op AggregateOp, field string) *AggregateBuilder {
+	a.fields = append(a.fields, AggregateField{Name: name, Op: op, Field: field})
+	return a
+}
+
+// Compile returns the structured aggregation, or an error if it has no
+// query, computes nothing or reuses a name
+func (a *AggregateBuilder) Compile() (StructuredAggregate, error) {
+	if a == nil || a.query == nil {
+		return StructuredAggregate{}, fmt.Errorf("aggregate: no query")
+	}
+	if len(a.fields) == 0 {
+		return StructuredAggregate{}, fmt.Errorf("aggregate: no aggregate fields")
+	}
+	names := make(map[string]bool)
+	for _, g := range a.groupBy {
+		names[g] = true
+	}
+	for _, f := range a.fields {
+		if f.Name == "" {
+			return StructuredAggregate{}, fmt.Errorf("aggregate: %s without a name", f.Op)
+		}
+		if names[f.Name] {
+			return StructuredAggregate{}, fmt.Errorf("aggregate: duplicate name %s", f.Name)
+		}
+		names[f.Name] = true
+	}
+	return StructuredAggregate{
+		Query:   a.query.CompileStructured(),
+		GroupBy: a.groupBy,
+		Fields:  a.fields,
+	}, nil
+}
+
+// AggregateRow is one output row of an aggregation
+type AggregateRow struct {
+	// Group holds the group by field values of the row
+	Group map[string]interface{} `json:"group"`
+	// Values holds the aggregate values by name
+	Values map[string]interface{} `json:"values"`
+}
+
+// Key returns the value of a group by field
+func (r AggregateRow) Key(field string) interface{} {
+	return r.Group[field]
+}
+
+// Value returns the aggregate stored under name, e.g. the result of Min on a string field
+func (r AggregateRow) Value(name string) (interface{}, bool) {
+	v, ok := r.Values[name]
+	return v, ok
+}
+
+// Float returns a numeric aggregate
+func (r AggregateRow) Float(name string) (float64, error) {
+	v, ok := r.Values[name]
+	if !ok {
+		return 0, fmt.Errorf("field %s: %w", name, ErrFieldNotFound)
+	}
+	f, ok := toFloat(v)
+	if !ok {
+		return 0, &FieldTypeError{Path: name, Want: "number", Got: v}
+	}
+	return f, nil
+}
+
+// Int64 returns an integer aggregate, such as a count
+func (r AggregateRow) Int64(name string) (int64, error) {
+	f, err := r.Float(name)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := floatInt64(f)
+	if !ok {
+		return 0, &FieldTypeError{Path: name, Want: "integer", Got: r.Values[name]}
+	}
+	return n, nil
+}
+
+// Decode the group fields and aggregate values of the row into the struct
+// pointed to by v. Fields map by name as in FromDocument, but a row has no
+// metadata, so fields named id, created_at or updated_at are plain data.
+func (r AggregateRow) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("sqrl: decode target must be a non-nil pointer, got %T", v)
+	}
+	data := make(map[string]interface{}, len(r.Group)+len(r.Values))
+	for k, val := range r.Group {
+		data[k] = val
+	}
+	for k, val := range r.Values {
+		data[k] = val
+	}
+	return decodeValue(rv.Elem(), data, "")
+}
+
+// Aggregate runs an aggregation on the server
+func (c *Client) Aggregate(ctx context.Context, a *AggregateBuilder) ([]AggregateRow, error) {
+	compiled, err := a.Compile()
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.request(ctx, map[string]interface{}{
+		"type":      "Aggregate",
+		"aggregate": compiled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct{ Rows []AggregateRow }
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Rows, nil
+}
+
+// Count returns the number of documents matching q's filters
+func (c *Client) Count(ctx context.Context, q *QueryBuilder) (int64, error) {
+	if q == nil {
+		return 0, fmt.Errorf("count: no query")
+	}
+	q = q.clone()
+	q.sorts, q.skipValue, q.limitValue = nil, nil, nil
+	rows, err := c.Aggregate(ctx, Aggregate(q).Count("count"))
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].Int64("count")
+}
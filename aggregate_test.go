@@ -0,0 +1,147 @@
+// SquirrelDB Go SDK - Aggregation Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func fakeOrders() []Document {
+	order := func(id, country, customer string, total float64) Document {
+		return Document{Id: id, Collection: "orders", Data: map[string]interface{}{
+			"country": country, "customer": customer, "total": total,
+		}}
+	}
+	return []Document{
+		order("o1", "FR", "alice", 10),
+		order("o2", "FR", "alice", 30),
+		order("o3", "FR", "bob", 20),
+		order("o4", "DE", "carol", 5),
+		order("o5", "US", "dave", 100),
+	}
+}
+
+func TestAggregateCompile(t *testing.T) {
+	compiled, err := Aggregate(Table("orders").Find(Field("total").Gt(0))).
+		GroupBy("country").
+		Sum("total", "revenue").
+		CountDistinct("customer", "customers").
+		Compile()
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if compiled.Query.Table != "orders" || compiled.Query.Filter == nil {
+		t.Errorf("Unexpected query: %+v", compiled.Query)
+	}
+	if len(compiled.GroupBy) != 1 || len(compiled.Fields) != 2 || compiled.Fields[1].Op != AggCountDistinct {
+		t.Errorf("Unexpected aggregation: %+v", compiled)
+	}
+
+	if _, err := Aggregate(nil).Count("n").Compile(); err == nil {
+		t.Error("Expected error without a query")
+	}
+	var none *AggregateBuilder
+	if _, err := none.Compile(); err == nil {
+		t.Error("Expected error for a nil builder")
+	}
+	if _, err := Aggregate(Table("orders")).Compile(); err == nil {
+		t.Error("Expected error without aggregate fields")
+	}
+	if _, err := Aggregate(Table("orders")).GroupBy("n").Count("n").Compile(); err == nil {
+		t.Error("Expected error for name clashing with group field")
+	}
+}
+
+func TestClientAggregate(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.serveDocuments(fakeOrders()...)
+	client := srv.connect(nil)
+
+	rows, err := client.Aggregate(context.Background(), Aggregate(Table("orders").Sort("revenue", SortDesc).Limit(2)).
+		GroupBy("country").
+		Count("orders").
+		Sum("total", "revenue").
+		Avg("total", "average").
+		Min("total", "smallest").
+		Max("customer", "last_customer").
+		CountDistinct("customer", "customers"))
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Key("country") != "US" || rows[1].Key("country") != "FR" {
+		t.Fatalf("Unexpected rows: %+v", rows)
+	}
+
+	fr := rows[1]
+	if n, err := fr.Int64("orders"); err != nil || n != 3 {
+		t.Errorf("Expected 3 orders, got %d, %v", n, err)
+	}
+	if avg, err := fr.Float("average"); err != nil || avg != 20 {
+		t.Errorf("Expected average 20, got %v, %v", avg, err)
+	}
+	if v, _ := fr.Value("last_customer"); v != "bob" {
+		t.Errorf("Expected max customer bob, got %v", v)
+	}
+	if _, err := fr.Int64("average"); err != nil {
+		t.Errorf("Expected whole average to convert, got %v", err)
+	}
+	if _, err := fr.Float("missing"); !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("Expected ErrFieldNotFound, got %v", err)
+	}
+
+	var decoded struct {
+		Country   string  `sqrl:"country"`
+		Revenue   float64 `sqrl:"revenue"`
+		Customers int     `sqrl:"customers"`
+		Smallest  float64 `sqrl:"smallest"`
+	}
+	if err := fr.Decode(&decoded); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if decoded.Country != "FR" || decoded.Revenue != 60 || decoded.Customers != 2 || decoded.Smallest != 10 {
+		t.Errorf("Unexpected decoded row: %+v", decoded)
+	}
+}
+
+func TestAggregateRowDecodeMetadataNames(t *testing.T) {
+	row := AggregateRow{
+		Group:  map[string]interface{}{"id": "org-1"},
+		Values: map[string]interface{}{"created_at": float64(3), "updated_at": "2024-05-01T10:00:00Z", "big": 0x1p63},
+	}
+	var decoded struct {
+		ID        string    `sqrl:"id"`
+		CreatedAt int       `sqrl:"created_at"`
+		UpdatedAt time.Time `sqrl:"updated_at"`
+	}
+	if err := row.Decode(&decoded); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if decoded.ID != "org-1" || decoded.CreatedAt != 3 || decoded.UpdatedAt.IsZero() {
+		t.Errorf("Expected metadata names to decode as row data, got %+v", decoded)
+	}
+	if _, err := row.Int64("big"); err == nil {
+		t.Error("Expected out of range integer to be rejected")
+	}
+}
+
+func TestClientCount(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.serveDocuments(fakeOrders()...)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	n, err := client.Count(ctx, Table("orders").Find(Field("country").Eq("FR")).Limit(1))
+	if err != nil || n != 3 {
+		t.Errorf("Expected 3 matching orders, got %d, %v", n, err)
+	}
+	n, err = client.Count(ctx, Table("orders").Find(Field("country").Eq("JP")))
+	if err != nil || n != 0 {
+		t.Errorf("Expected no matching orders, got %d, %v", n, err)
+	}
+	if len(srv.messages("Query")) != 0 {
+		t.Error("Expected Count not to fetch documents")
+	}
+}
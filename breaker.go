@@ -0,0 +1,233 @@
+// SquirrelDB Go SDK - Circuit Breaker
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the server while a circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerState represents the state of a circuit breaker
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// BreakerOptions configures a circuit breaker
+type BreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit
+	FailureThreshold int
+	// SuccessThreshold is the number of half-open successes that closes the circuit
+	SuccessThreshold int
+	// OpenTimeout is how long the circuit stays open before probing again
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests limits concurrent probes while half-open
+	HalfOpenMaxRequests int
+	// IsFailure reports whether an error counts against the circuit.
+	// Defaults to counting transport failures and retryable error replies,
+	// but not context cancellation or errors caused by the request itself.
+	IsFailure func(error) bool
+	// OnStateChange is called after every state transition
+	OnStateChange func(from, to BreakerState)
+}
+
+// CircuitBreaker fails calls fast while the wrapped service is unhealthy.
+// A single breaker may be shared by Client, Cache and Storage.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	opts      BreakerOptions
+	state     BreakerState
+	failures  int
+	successes int
+	inFlight  int
+	// generation counts state transitions, so that calls admitted in an
+	// earlier state do not count towards the current one
+	generation uint64
+	openedAt   time.Time
+	now        func() time.Time
+}
+
+// NewCircuitBreaker creates a circuit breaker in the closed state
+func NewCircuitBreaker(opts *BreakerOptions) *CircuitBreaker {
+	o := BreakerOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.FailureThreshold <= 0 {
+		o.FailureThreshold = 5
+	}
+	if o.SuccessThreshold <= 0 {
+		o.SuccessThreshold = 1
+	}
+	if o.OpenTimeout <= 0 {
+		o.OpenTimeout = 30 * time.Second
+	}
+	if o.HalfOpenMaxRequests <= 0 {
+		o.HalfOpenMaxRequests = 1
+	}
+	if o.IsFailure == nil {
+		o.IsFailure = isBreakerFailure
+	}
+	return &CircuitBreaker{opts: o, now: time.Now}
+}
+
+func isBreakerFailure(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var serverErr *ServerError
+	if errors.As(err, &serverErr) {
+		// Transaction conflicts are retryable but say nothing about server health
+		return serverErr.Retryable() && serverErr.Code != CodeSerializationFailure
+	}
+	var storageErr *StorageError
+	if errors.As(err, &storageErr) {
+		return storageErr.Retryable()
+	}
+	var cacheErr *CacheError
+	if errors.As(err, &cacheErr) {
+		return cacheErr.Retryable()
+	}
+	return true
+}
+
+// State returns the current state
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.opts.OpenTimeout {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// Execute runs fn if the circuit allows it and records the outcome.
+// A nil breaker always runs fn.
+func (b *CircuitBreaker) Execute(fn func() error) error {
+	if b == nil {
+		return fn()
+	}
+	gen, err := b.allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	b.record(gen, err)
+	return err
+}
+
+// allow admits a call, returning the generation it was admitted in
+func (b *CircuitBreaker) allow() (uint64, error) {
+	b.mu.Lock()
+	var from BreakerState
+	changed := false
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.opts.OpenTimeout {
+		from, changed = b.transition(BreakerHalfOpen)
+	}
+	var err error
+	switch b.state {
+	case BreakerOpen:
+		err = ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.inFlight >= b.opts.HalfOpenMaxRequests {
+			err = ErrCircuitOpen
+		} else {
+			b.inFlight++
+		}
+	}
+	gen := b.generation
+	b.mu.Unlock()
+
+	if changed {
+		b.notify(from, BreakerHalfOpen)
+	}
+	return gen, err
+}
+
+// record counts the outcome of a call admitted in generation gen; outcomes
+// of calls admitted before the last state change are ignored
+func (b *CircuitBreaker) record(gen uint64, err error) {
+	failed := err != nil && b.opts.IsFailure(err)
+
+	b.mu.Lock()
+	if gen != b.generation {
+		b.mu.Unlock()
+		return
+	}
+	var from, to BreakerState
+	changed := false
+	switch b.state {
+	case BreakerClosed:
+		if failed {
+			b.failures++
+			if b.failures >= b.opts.FailureThreshold {
+				to = BreakerOpen
+				from, changed = b.transition(to)
+			}
+		} else {
+			b.failures = 0
+		}
+	case BreakerHalfOpen:
+		if b.inFlight > 0 {
+			b.inFlight--
+		}
+		if failed {
+			to = BreakerOpen
+			from, changed = b.transition(to)
+		} else {
+			b.successes++
+			if b.successes >= b.opts.SuccessThreshold {
+				to = BreakerClosed
+				from, changed = b.transition(to)
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	if changed {
+		b.notify(from, to)
+	}
+}
+
+// transition moves to a new state; the caller must hold b.mu
+func (b *CircuitBreaker) transition(to BreakerState) (BreakerState, bool) {
+	from := b.state
+	if from == to {
+		return from, false
+	}
+	b.state = to
+	b.generation++
+	b.failures = 0
+	b.successes = 0
+	b.inFlight = 0
+	if to == BreakerOpen {
+		b.openedAt = b.now()
+	}
+	return from, true
+}
+
+func (b *CircuitBreaker) notify(from, to BreakerState) {
+	if b.opts.OnStateChange != nil {
+		b.opts.OnStateChange(from, to)
+	}
+}
@@ -0,0 +1,139 @@
+// SquirrelDB Go SDK - Circuit Breaker Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterFailures(t *testing.T) {
+	var transitions []string
+	b := NewCircuitBreaker(&BreakerOptions{
+		FailureThreshold: 2,
+		OnStateChange: func(from, to BreakerState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+	boom := errors.New("boom")
+
+	b.Execute(func() error { return boom })
+	if b.State() != BreakerClosed {
+		t.Fatalf("Expected closed after one failure, got %s", b.State())
+	}
+	b.Execute(func() error { return boom })
+	if b.State() != BreakerOpen {
+		t.Fatalf("Expected open after two failures, got %s", b.State())
+	}
+
+	called := false
+	err := b.Execute(func() error { called = true; return nil })
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if called {
+		t.Error("Expected call to be short-circuited")
+	}
+	if len(transitions) != 1 || transitions[0] != "closed->open" {
+		t.Errorf("Unexpected transitions: %v", transitions)
+	}
+}
+
+func TestCircuitBreakerHalfOpenRecovery(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewCircuitBreaker(&BreakerOptions{FailureThreshold: 1, OpenTimeout: time.Second})
+	b.now = func() time.Time { return now }
+
+	b.Execute(func() error { return errors.New("boom") })
+	if b.State() != BreakerOpen {
+		t.Fatalf("Expected open, got %s", b.State())
+	}
+
+	now = now.Add(time.Second)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("Expected half-open after timeout, got %s", b.State())
+	}
+	if err := b.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("Expected probe to run, got %v", err)
+	}
+	if b.State() != BreakerClosed {
+		t.Errorf("Expected closed after successful probe, got %s", b.State())
+	}
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewCircuitBreaker(&BreakerOptions{FailureThreshold: 1, OpenTimeout: time.Second})
+	b.now = func() time.Time { return now }
+
+	b.Execute(func() error { return errors.New("boom") })
+	now = now.Add(time.Second)
+	b.Execute(func() error { return errors.New("still down") })
+	if b.State() != BreakerOpen {
+		t.Errorf("Expected open after failed probe, got %s", b.State())
+	}
+}
+
+func TestCircuitBreakerIgnoresStaleResults(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewCircuitBreaker(&BreakerOptions{FailureThreshold: 1, OpenTimeout: time.Second})
+	b.now = func() time.Time { return now }
+
+	// A slow call is admitted while closed and outlives two state changes
+	slow, err := b.allow()
+	if err != nil {
+		t.Fatalf("Expected slow call to be admitted, got %v", err)
+	}
+	b.Execute(func() error { return errors.New("boom") })
+	now = now.Add(time.Second)
+	probe, err := b.allow()
+	if err != nil || b.State() != BreakerHalfOpen {
+		t.Fatalf("Expected a half-open probe to be admitted, got %v in %s", err, b.State())
+	}
+
+	b.record(slow, nil)
+	if b.State() != BreakerHalfOpen {
+		t.Errorf("Expected stale success not to close the circuit, got %s", b.State())
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected stale result not to free the probe slot, got %v", err)
+	}
+
+	b.record(probe, nil)
+	if b.State() != BreakerClosed {
+		t.Errorf("Expected successful probe to close the circuit, got %s", b.State())
+	}
+}
+
+func TestCircuitBreakerIgnoresContextErrors(t *testing.T) {
+	b := NewCircuitBreaker(&BreakerOptions{FailureThreshold: 1})
+	b.Execute(func() error { return context.Canceled })
+	b.Execute(func() error { return &StorageError{StatusCode: 404} })
+	b.Execute(func() error { return &ServerError{Code: CodeValidation} })
+	if b.State() != BreakerClosed {
+		t.Errorf("Expected closed, got %s", b.State())
+	}
+}
+
+func TestClientFailsFastWhenCircuitOpen(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Query", func(msg map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "Error", "code": "unavailable", "message": "overloaded", "retryable": true}
+	})
+	client := srv.connect(&Options{Breaker: NewCircuitBreaker(&BreakerOptions{FailureThreshold: 2})})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := client.Query(ctx, `db.table("users").run()`); err == nil {
+			t.Fatal("Expected query to fail")
+		}
+	}
+	if _, err := client.Query(ctx, `db.table("users").run()`); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if n := len(srv.messages("Query")); n != 2 {
+		t.Errorf("Expected 2 queries to reach the server, got %d", n)
+	}
+}
@@ -0,0 +1,274 @@
+// SquirrelDB Go SDK - Bulk Operations
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrMessageTooLarge is reported for a bulk item that does not fit in a
+// single message on its own
+var ErrMessageTooLarge = errors.New("message exceeds MaxMessageSize")
+
+// BulkItem is the outcome of one item of a bulk operation
+type BulkItem struct {
+	// Index of the item in the input slice
+	Index    int
+	Document *Document
+	Err      error
+	// Queued is set when the item was stored in the offline queue instead of
+	// being sent; its outcome is reported to OfflineQueueOptions.OnReplay
+	Queued bool
+}
+
+// BulkResult lists the outcome of every item of a bulk operation, in input order
+type BulkResult struct {
+	Items []BulkItem
+}
+
+// Succeeded returns the items that were applied
+func (r *BulkResult) Succeeded() []BulkItem {
+	var out []BulkItem
+	for _, item := range r.Items {
+		if item.Err == nil && !item.Queued {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Queued returns the items stored in the offline queue for replay
+func (r *BulkResult) Queued() []BulkItem {
+	var out []BulkItem
+	for _, item := range r.Items {
+		if item.Queued {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Failed returns the items that were not applied
+func (r *BulkResult) Failed() []BulkItem {
+	var out []BulkItem
+	for _, item := range r.Items {
+		if item.Err != nil {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Err returns a *BulkError if any item failed, nil otherwise. Queued items
+// have not failed.
+func (r *BulkResult) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return &BulkError{Total: len(r.Items), Failed: failed}
+}
+
+// BulkError is returned when some items of a bulk operation failed. It
+// matches the item errors with errors.Is and errors.As.
+type BulkError struct {
+	Total  int
+	Failed []BulkItem
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("bulk: %d of %d items failed, first: item %d: %v",
+		len(e.Failed), e.Total, e.Failed[0].Index, e.Failed[0].Err)
+}
+
+// Unwrap returns the item errors
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, item := range e.Failed {
+		errs[i] = item.Err
+	}
+	return errs
+}
+
+// BulkUpdate is one item of UpdateMany
+type BulkUpdate struct {
+	ID   string
+	Data map[string]interface{}
+}
+
+// InsertMany inserts documents in as few requests as fit under
+// MaxMessageSize. The result is always returned; the error is a *BulkError
+// if any document failed.
+func (c *Client) InsertMany(ctx context.Context, collection string, data []map[string]interface{}) (*BulkResult, error) {
+	items := make([]interface{}, len(data))
+	invalid := make([]error, len(data))
+	for i, d := range data {
+		items[i] = d
+		invalid[i] = c.validate(collection, d)
+	}
+	return c.bulk(ctx, "InsertMany", collection, "documents", items, invalid)
+}
+
+// UpdateMany replaces the data of several documents, see InsertMany
+func (c *Client) UpdateMany(ctx context.Context, collection string, updates []BulkUpdate) (*BulkResult, error) {
+	items := make([]interface{}, len(updates))
+	invalid := make([]error, len(updates))
+	for i, u := range updates {
+		items[i] = map[string]interface{}{"document_id": u.ID, "data": u.Data}
+		invalid[i] = c.validate(collection, u.Data)
+	}
+	return c.bulk(ctx, "UpdateMany", collection, "updates", items, invalid)
+}
+
+// DeleteMany deletes documents by id, see InsertMany
+func (c *Client) DeleteMany(ctx context.Context, collection string, ids []string) (*BulkResult, error) {
+	items := make([]interface{}, len(ids))
+	for i, id := range ids {
+		items[i] = id
+	}
+	return c.bulk(ctx, "DeleteMany", collection, "document_ids", items, nil)
+}
+
+// DeleteWhere deletes every document matching the query's filters in a
+// single request and returns the number deleted. Sort, skip and limit are
+// ignored.
+func (c *Client) DeleteWhere(ctx context.Context, q *QueryBuilder) (int, error) {
+	compiled := q.CompileStructured()
+	if compiled.Filter == nil {
+		return 0, errors.New("delete: empty filter")
+	}
+	result, err := c.write(ctx, map[string]interface{}{
+		"type":       "DeleteMany",
+		"collection": compiled.Table,
+		"filter":     compiled.Filter,
+	})
+	if err != nil {
+		return 0, err
+	}
+	var resp struct{ Deleted int }
+	json.Unmarshal(result, &resp)
+	return resp.Deleted, nil
+}
+
+// bulkBatch is a run of items sent in one message, with their input indexes
+type bulkBatch struct {
+	indexes []int
+	items   []interface{}
+}
+
+// bulk splits items into batches under the message size limit and sends up
+// to bulkLimit batches concurrently. Items with an error in invalid, which
+// may be nil, fail without being sent.
+func (c *Client) bulk(ctx context.Context, msgType, collection, key string, items []interface{}, invalid []error) (*BulkResult, error) {
+	result := &BulkResult{Items: make([]BulkItem, len(items))}
+	for i := range result.Items {
+		result.Items[i].Index = i
+	}
+
+	var batches []bulkBatch
+	cur := bulkBatch{}
+	size := 0
+	for i, item := range items {
+		if invalid != nil && invalid[i] != nil {
+			result.Items[i].Err = invalid[i]
+			continue
+		}
+		raw, err := json.Marshal(item)
+		if err != nil {
+			result.Items[i].Err = err
+			continue
+		}
+		n := len(raw) + 1
+		if n > c.maxBatchBytes {
+			result.Items[i].Err = ErrMessageTooLarge
+			continue
+		}
+		if len(cur.items) > 0 && size+n > c.maxBatchBytes {
+			batches = append(batches, cur)
+			cur, size = bulkBatch{}, 0
+		}
+		cur.indexes = append(cur.indexes, i)
+		cur.items = append(cur.items, item)
+		size += n
+	}
+	if len(cur.items) > 0 {
+		batches = append(batches, cur)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.bulkLimit)
+	for _, b := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(b bulkBatch) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.sendBatch(ctx, msgType, collection, key, b, result)
+		}(b)
+	}
+	wg.Wait()
+	return result, result.Err()
+}
+
+// sendBatch sends one batch and records the outcome of its items. A failed
+// request fails every item of the batch and a queued one marks them queued.
+func (c *Client) sendBatch(ctx context.Context, msgType, collection, key string, b bulkBatch, result *BulkResult) {
+	fail := func(err error) {
+		for _, i := range b.indexes {
+			result.Items[i].Err = err
+		}
+	}
+
+	raw, err := c.write(ctx, map[string]interface{}{
+		"type":       msgType,
+		"collection": collection,
+		key:          b.items,
+	})
+	if errors.Is(err, ErrQueued) {
+		for _, i := range b.indexes {
+			result.Items[i].Queued = true
+		}
+		return
+	}
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	var resp struct {
+		Results []struct {
+			Document *Document       `json:"document"`
+			Error    json.RawMessage `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		fail(err)
+		return
+	}
+	if len(resp.Results) != len(b.indexes) {
+		fail(fmt.Errorf("bulk: expected %d results, got %d", len(b.indexes), len(resp.Results)))
+		return
+	}
+	for j, r := range resp.Results {
+		item := &result.Items[b.indexes[j]]
+		if len(r.Error) > 0 && string(r.Error) != "null" {
+			item.Err = parseItemError(r.Error)
+			continue
+		}
+		item.Document = r.Document
+	}
+}
+
+// parseItemError builds the error of one bulk item, which the server sends
+// either as an error object or as a bare message string
+func parseItemError(raw json.RawMessage) *ServerError {
+	var message string
+	if err := json.Unmarshal(raw, &message); err == nil {
+		return &ServerError{Message: message}
+	}
+	return parseServerError(raw)
+}
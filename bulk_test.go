@@ -0,0 +1,132 @@
+// SquirrelDB Go SDK - Bulk Operations Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInsertManyBatchesAndReportsItemErrors(t *testing.T) {
+	srv := newFakeServer(t)
+	var inFlight, maxInFlight atomic.Int32
+	srv.handle("InsertMany", func(msg map[string]interface{}) map[string]interface{} {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		docs, _ := msg["documents"].([]interface{})
+		results := make([]interface{}, len(docs))
+		for i, d := range docs {
+			data, _ := d.(map[string]interface{})
+			if data["name"] == "bad" {
+				results[i] = map[string]interface{}{"error": map[string]interface{}{"code": CodeValidation, "message": "bad name"}}
+				continue
+			}
+			results[i] = map[string]interface{}{"document": map[string]interface{}{"id": fmt.Sprint("d-", data["n"]), "collection": "items", "data": data}}
+		}
+		return map[string]interface{}{"type": "Result", "results": results}
+	})
+	client := srv.connect(&Options{BulkConcurrency: 2})
+	client.maxBatchBytes = 50
+
+	var data []map[string]interface{}
+	for i := 0; i < 10; i++ {
+		data = append(data, map[string]interface{}{"n": i, "name": "ok"})
+	}
+	data[3]["name"] = "bad"
+	data[7]["name"] = strings.Repeat("x", 200)
+
+	res, err := client.InsertMany(context.Background(), "items", data)
+	var bulkErr *BulkError
+	if !errors.As(err, &bulkErr) {
+		t.Fatalf("Expected *BulkError, got %v", err)
+	}
+	if !errors.Is(err, ErrValidation) || !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("Expected item errors to be matchable, got %v", err)
+	}
+	if len(res.Items) != 10 || len(res.Succeeded()) != 8 {
+		t.Fatalf("Expected 8 of 10 items to succeed, got %+v", res.Items)
+	}
+	failed := res.Failed()
+	if len(failed) != 2 || failed[0].Index != 3 || failed[1].Index != 7 {
+		t.Errorf("Unexpected failed items: %+v", failed)
+	}
+	if doc := res.Items[9].Document; doc == nil || doc.Id != "d-9" {
+		t.Errorf("Expected item 9 to map to its document, got %+v", doc)
+	}
+
+	batches := srv.messages("InsertMany")
+	if len(batches) < 3 {
+		t.Errorf("Expected several batches, got %d", len(batches))
+	}
+	if maxInFlight.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent batches, got %d", maxInFlight.Load())
+	}
+}
+
+func TestBulkItemErrorAsString(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("InsertMany", func(msg map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "Result", "results": []interface{}{
+			map[string]interface{}{"error": "duplicate id"},
+		}}
+	})
+	client := srv.connect(nil)
+
+	res, _ := client.InsertMany(context.Background(), "items", []map[string]interface{}{{"name": "a"}})
+	var serverErr *ServerError
+	if failed := res.Failed(); len(failed) != 1 || !errors.As(failed[0].Err, &serverErr) || serverErr.Message != "duplicate id" {
+		t.Errorf("Expected the string error to be kept as the message, got %+v", failed)
+	}
+}
+
+func TestDeleteManyFailsWholeBatchOnRequestError(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("DeleteMany", func(msg map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "Error", "code": CodePermissionDenied, "message": "nope"}
+	})
+	client := srv.connect(nil)
+
+	res, err := client.DeleteMany(context.Background(), "items", []string{"a", "b"})
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("Expected permission denied, got %v", err)
+	}
+	if len(res.Failed()) != 2 {
+		t.Errorf("Expected both items to fail, got %+v", res.Items)
+	}
+}
+
+func TestDeleteWhere(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("DeleteMany", func(msg map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "Result", "deleted": 3}
+	})
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	n, err := client.DeleteWhere(ctx, Table("sessions").Find(Field("expired").Eq(true)))
+	if err != nil || n != 3 {
+		t.Fatalf("Expected 3 deletions, got %d, %v", n, err)
+	}
+	msg := srv.messages("DeleteMany")[0]
+	filter, _ := msg["filter"].(map[string]interface{})
+	if msg["collection"] != "sessions" || filter["expired"] == nil {
+		t.Errorf("Unexpected delete message: %v", msg)
+	}
+
+	if _, err := client.DeleteWhere(ctx, Table("sessions")); err == nil {
+		t.Error("Expected error for delete without filter")
+	}
+}
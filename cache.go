@@ -6,6 +6,7 @@ package squirreldb
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -18,12 +19,19 @@ import (
 type CacheOptions struct {
 	Host string
 	Port int
+	// Breaker fails commands fast while the server is unhealthy; nil disables it
+	Breaker *CircuitBreaker
+	// RateLimits throttles commands per operation type; nil disables limiting
+	RateLimits *CacheRateLimits
 }
 
 // Cache is a Redis-compatible cache client
 type Cache struct {
-	conn   net.Conn
-	reader *bufio.Reader
+	conn       net.Conn
+	reader     *bufio.Reader
+	breaker    *CircuitBreaker
+	rateLimits *CacheRateLimits
+	ctx        context.Context
 }
 
 // ConnectCache connects to cache server
@@ -43,7 +51,13 @@ func ConnectCache(opts *CacheOptions) (*Cache, error) {
 		return nil, err
 	}
 
-	return &Cache{conn: conn, reader: bufio.NewReader(conn)}, nil
+	return &Cache{
+		conn:       conn,
+		reader:     bufio.NewReader(conn),
+		breaker:    opts.Breaker,
+		rateLimits: opts.RateLimits,
+		ctx:        context.Background(),
+	}, nil
 }
 
 // Close the connection
@@ -51,7 +65,29 @@ func (c *Cache) Close() error {
 	return c.conn.Close()
 }
 
+// WithContext returns a view of the cache whose commands stop waiting for a
+// rate limit token once ctx is done. It shares the connection with c.
+func (c *Cache) WithContext(ctx context.Context) *Cache {
+	view := *c
+	view.ctx = ctx
+	return &view
+}
+
 func (c *Cache) command(args ...string) (interface{}, error) {
+	if err := c.rateLimits.forCommand(args[0]).acquire(c.ctx); err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	err := c.breaker.Execute(func() error {
+		var err error
+		result, err = c.roundTrip(args...)
+		return err
+	})
+	return result, err
+}
+
+func (c *Cache) roundTrip(args ...string) (interface{}, error) {
 	cmd := fmt.Sprintf("*%d\r\n", len(args))
 	for _, arg := range args {
 		cmd += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
@@ -74,7 +110,7 @@ func (c *Cache) readResponse() (interface{}, error) {
 	case '+':
 		return line[1:], nil
 	case '-':
-		return nil, errors.New(line[1:])
+		return nil, parseCacheError(line[1:])
 	case ':':
 		return strconv.ParseInt(line[1:], 10, 64)
 	case '$':
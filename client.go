@@ -28,24 +28,52 @@ type Options struct {
 	Host      string
 	Port      int
 	AuthToken string
+	// Retry controls retries of failed requests; nil disables retries
+	Retry *RetryPolicy
+	// Breaker fails requests fast while the server is unhealthy; nil disables it
+	Breaker *CircuitBreaker
+	// RateLimits throttles requests per operation type; nil disables limiting
+	RateLimits *RateLimits
+	// OfflineQueue stores mutations made while disconnected and replays them
+	// on reconnect; nil makes mutations fail with ErrClosed instead
+	OfflineQueue *OfflineQueue
+	// BulkConcurrency limits the batches of a bulk operation in flight at
+	// once; defaults to 4
+	BulkConcurrency int
+	// TransactionAttempts limits how many times Transaction runs a function
+	// aborted by a serialization conflict, and Modify a read-modify-write
+	// cycle whose write conflicted; defaults to 3
+	TransactionAttempts int
 }
 
 // Client is a SquirrelDB WebSocket client
 type Client struct {
+	url           string
 	conn          *websocket.Conn
 	pending       sync.Map
 	subscriptions sync.Map
 	requestID     atomic.Int64
 	closed        atomic.Bool
 	mu            sync.Mutex
+	retry         *RetryPolicy
+	breaker       *CircuitBreaker
+	rateLimits    *RateLimits
+	offline       *OfflineQueue
+	bulkLimit     int
+	maxBatchBytes int
+	txAttempts    int
+	schemas       sync.Map // collection -> *Schema
 }
 
 type pendingRequest struct {
-	ch  chan json.RawMessage
-	err chan error
+	conn *websocket.Conn
+	ch   chan json.RawMessage
+	err  chan error
 }
 
-// Connect to SquirrelDB server
+// Connect to SquirrelDB server.
+// With an offline queue configured, an unreachable server yields a
+// disconnected client that queues mutations until Reconnect succeeds.
 func Connect(ctx context.Context, opts *Options) (*Client, error) {
 	if opts == nil {
 		opts = &Options{Host: "localhost", Port: 8080}
@@ -58,21 +86,78 @@ func Connect(ctx context.Context, opts *Options) (*Client, error) {
 	}
 
 	u := url.URL{Scheme: "ws", Host: fmt.Sprintf("%s:%d", opts.Host, opts.Port)}
-	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
+	client := &Client{
+		url:        u.String(),
+		retry:      opts.Retry,
+		breaker:    opts.Breaker,
+		rateLimits: opts.RateLimits,
+		offline:    opts.OfflineQueue,
+		bulkLimit:  opts.BulkConcurrency,
+		txAttempts: opts.TransactionAttempts,
+		// Leave room for the envelope around batched items
+		maxBatchBytes: MaxMessageSize - 4096,
+	}
+	if client.bulkLimit <= 0 {
+		client.bulkLimit = 4
+	}
+	if client.txAttempts <= 0 {
+		client.txAttempts = 3
+	}
+	if err := client.dial(ctx); err != nil {
+		if client.offline == nil {
+			return nil, err
+		}
+		client.closed.Store(true)
+		return client, nil
+	}
+	if client.offline != nil {
+		// A lost connection leaves the client offline as if dialing failed;
+		// anything else stopping the replay is reported
+		if err := client.offline.Replay(ctx, client); err != nil && !isDisconnected(err) {
+			client.Close()
+			return nil, fmt.Errorf("replay offline queue: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// dial opens a new connection and starts listening on it
+func (c *Client) dial(ctx context.Context) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.url, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	client := &Client{conn: conn}
-	go client.listen()
-	return client, nil
+	c.mu.Lock()
+	old := c.conn
+	c.conn = conn
+	c.closed.Store(false)
+	c.mu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+
+	go c.listen(conn)
+	return nil
 }
 
-func (c *Client) listen() {
+// Reconnect replaces the connection and replays any queued offline
+// mutations. Subscriptions made on the previous connection are not restored.
+func (c *Client) Reconnect(ctx context.Context) error {
+	if err := c.dial(ctx); err != nil {
+		return err
+	}
+	if c.offline != nil {
+		return c.offline.Replay(ctx, c)
+	}
+	return nil
+}
+
+func (c *Client) listen(conn *websocket.Conn) {
+	defer c.disconnected(conn)
 	for {
-		_, message, err := c.conn.ReadMessage()
+		_, message, err := conn.ReadMessage()
 		if err != nil {
-			c.closed.Store(true)
 			return
 		}
 
@@ -91,9 +176,7 @@ func (c *Client) listen() {
 			if v, ok := c.pending.LoadAndDelete(id); ok {
 				req := v.(*pendingRequest)
 				if msgType == "Error" {
-					var errMsg string
-					json.Unmarshal(msg["message"], &errMsg)
-					req.err <- errors.New(errMsg)
+					req.err <- parseServerError(message)
 				} else {
 					req.ch <- message
 				}
@@ -111,20 +194,55 @@ func (c *Client) listen() {
 	}
 }
 
+// disconnected fails the requests still waiting on conn and, unless conn
+// has already been replaced, marks the client closed
+func (c *Client) disconnected(conn *websocket.Conn) {
+	c.mu.Lock()
+	if c.conn == conn {
+		c.closed.Store(true)
+	}
+	c.mu.Unlock()
+
+	c.pending.Range(func(key, v interface{}) bool {
+		if req := v.(*pendingRequest); req.conn == conn && c.pending.CompareAndDelete(key, v) {
+			req.err <- ErrClosed
+		}
+		return true
+	})
+}
+
 func (c *Client) send(ctx context.Context, msg map[string]interface{}) (json.RawMessage, error) {
+	msgType, _ := msg["type"].(string)
+	if err := c.rateLimits.forMessage(msgType).acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	var result json.RawMessage
+	err := c.breaker.Execute(func() error {
+		var err error
+		result, err = c.roundTrip(ctx, msg)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) roundTrip(ctx context.Context, msg map[string]interface{}) (json.RawMessage, error) {
 	if c.closed.Load() {
 		return nil, ErrClosed
 	}
 
 	id := fmt.Sprintf("req-%d", c.requestID.Add(1))
 	msg["id"] = id
+	data, _ := json.Marshal(msg)
 
-	req := &pendingRequest{ch: make(chan json.RawMessage, 1), err: make(chan error, 1)}
+	c.mu.Lock()
+	req := &pendingRequest{conn: c.conn, ch: make(chan json.RawMessage, 1), err: make(chan error, 1)}
 	c.pending.Store(id, req)
 	defer c.pending.Delete(id)
-
-	data, _ := json.Marshal(msg)
-	c.mu.Lock()
+	if c.closed.Load() {
+		c.mu.Unlock()
+		return nil, ErrClosed
+	}
 	err := c.conn.WriteMessage(websocket.TextMessage, data)
 	c.mu.Unlock()
 	if err != nil {
@@ -137,13 +255,92 @@ func (c *Client) send(ctx context.Context, msg map[string]interface{}) (json.Raw
 	case err := <-req.err:
 		return nil, err
 	case <-ctx.Done():
+		c.cancel(req.conn, id)
 		return nil, ctx.Err()
 	}
 }
 
+// cancel tells the server that nobody is waiting for request id any more.
+//
+// The server is expected to stop executing the request if it is still
+// running: a query is aborted, a mutation not yet applied is dropped and a
+// subscription being set up is torn down. It may answer the original id
+// with an Error coded "cancelled", which the client discards. Cancel itself
+// is never answered, and cancelling an unknown or finished request is a no-op.
+func (c *Client) cancel(conn *websocket.Conn, id string) {
+	data, _ := json.Marshal(map[string]interface{}{"type": "Cancel", "request_id": id})
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != conn || c.closed.Load() {
+		return
+	}
+	conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// request sends msg, retrying transient failures under the client's retry policy
+func (c *Client) request(ctx context.Context, msg map[string]interface{}) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := c.retry.do(ctx, func() error {
+		var err error
+		result, err = c.send(ctx, msg)
+		return err
+	})
+	return result, err
+}
+
+// mutate sends a write and returns the affected document
+func (c *Client) mutate(ctx context.Context, msg map[string]interface{}) (*Document, error) {
+	result, err := c.write(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	return firstDocument(result), nil
+}
+
+// write sends a mutation tagged with an idempotency key, so that retried
+// attempts are deduplicated by the server, queueing it while offline
+func (c *Client) write(ctx context.Context, msg map[string]interface{}) (json.RawMessage, error) {
+	if err := c.validateMessage(msg); err != nil {
+		return nil, err
+	}
+	msg["idempotency_key"] = newIdempotencyKey()
+	if c.offline != nil && !c.closed.Load() && c.offline.Len() > 0 {
+		// Resume a replay stopped by a transient failure; the write goes
+		// direct if the queue drains and is queued behind it otherwise
+		c.offline.Replay(ctx, c)
+	}
+	if c.offline != nil && (c.closed.Load() || c.offline.Len() > 0) {
+		// Keep writes in order behind anything still waiting for replay
+		return nil, c.offline.enqueue(msg)
+	}
+	result, err := c.request(ctx, msg)
+	if err != nil {
+		if c.offline != nil && isDisconnected(err) {
+			return nil, c.offline.enqueue(msg)
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// firstDocument decodes the first document of a Result message, if any
+func firstDocument(result json.RawMessage) *Document {
+	var resp struct{ Documents []Document }
+	json.Unmarshal(result, &resp)
+	if len(resp.Documents) > 0 {
+		return &resp.Documents[0]
+	}
+	return nil
+}
+
 // Close the connection
 func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.closed.Store(true)
+	if c.conn == nil {
+		return nil
+	}
 	return c.conn.Close()
 }
 
@@ -151,12 +348,15 @@ func (c *Client) Close() error {
 func (c *Client) Ping(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if c.conn == nil {
+		return ErrNotConnected
+	}
 	return c.conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Ping"}`))
 }
 
 // ListCollections returns all collections
 func (c *Client) ListCollections(ctx context.Context) ([]string, error) {
-	result, err := c.send(ctx, map[string]interface{}{"type": "ListCollections"})
+	result, err := c.request(ctx, map[string]interface{}{"type": "ListCollections"})
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +367,7 @@ func (c *Client) ListCollections(ctx context.Context) ([]string, error) {
 
 // Query executes a query
 func (c *Client) Query(ctx context.Context, query string) ([]Document, error) {
-	result, err := c.send(ctx, map[string]interface{}{"type": "Query", "query": query})
+	result, err := c.request(ctx, map[string]interface{}{"type": "Query", "query": query})
 	if err != nil {
 		return nil, err
 	}
@@ -176,64 +376,116 @@ func (c *Client) Query(ctx context.Context, query string) ([]Document, error) {
 	return resp.Documents, nil
 }
 
-// Insert a document
-func (c *Client) Insert(ctx context.Context, collection string, data map[string]interface{}) (*Document, error) {
-	result, err := c.send(ctx, map[string]interface{}{
-		"type":       "Insert",
-		"collection": collection,
-		"data":       data,
+// Find executes a query built with QueryBuilder, sent in structured form
+func (c *Client) Find(ctx context.Context, q *QueryBuilder) ([]Document, error) {
+	result, err := c.request(ctx, map[string]interface{}{
+		"type":             "Query",
+		"structured_query": q.CompileStructured(),
 	})
 	if err != nil {
 		return nil, err
 	}
 	var resp struct{ Documents []Document }
 	json.Unmarshal(result, &resp)
-	if len(resp.Documents) > 0 {
-		return &resp.Documents[0], nil
+	return resp.Documents, nil
+}
+
+// Get a document by id, returning a *NotFoundError if it does not exist
+func (c *Client) Get(ctx context.Context, collection, id string) (*Document, error) {
+	docs, err := c.Find(ctx, Table(collection).Find(Field("id").Eq(id)).Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, &NotFoundError{Collection: collection, ID: id}
+	}
+	return &docs[0], nil
+}
+
+// GetMany fetches documents by id in a single request. Documents are
+// returned in the order of ids; ids that do not exist are skipped.
+func (c *Client) GetMany(ctx context.Context, collection string, ids []string) ([]Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	docs, err := c.Find(ctx, Table(collection).Find(Field("id").In(values...)).Limit(len(ids)))
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]Document, len(docs))
+	for _, doc := range docs {
+		byID[doc.Id] = doc
 	}
-	return nil, nil
+	result := make([]Document, 0, len(docs))
+	for _, id := range ids {
+		if doc, ok := byID[id]; ok {
+			result = append(result, doc)
+			delete(byID, id)
+		}
+	}
+	return result, nil
+}
+
+// Exists checks whether a document with the given id exists
+func (c *Client) Exists(ctx context.Context, collection, id string) (bool, error) {
+	_, err := c.Get(ctx, collection, id)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Insert a document
+func (c *Client) Insert(ctx context.Context, collection string, data map[string]interface{}) (*Document, error) {
+	return c.mutate(ctx, map[string]interface{}{
+		"type":       "Insert",
+		"collection": collection,
+		"data":       data,
+	})
 }
 
 // Update a document
 func (c *Client) Update(ctx context.Context, collection, id string, data map[string]interface{}) (*Document, error) {
-	result, err := c.send(ctx, map[string]interface{}{
+	return c.mutate(ctx, map[string]interface{}{
 		"type":        "Update",
 		"collection":  collection,
 		"document_id": id,
 		"data":        data,
 	})
-	if err != nil {
-		return nil, err
-	}
-	var resp struct{ Documents []Document }
-	json.Unmarshal(result, &resp)
-	if len(resp.Documents) > 0 {
-		return &resp.Documents[0], nil
-	}
-	return nil, nil
 }
 
 // Delete a document
 func (c *Client) Delete(ctx context.Context, collection, id string) (*Document, error) {
-	result, err := c.send(ctx, map[string]interface{}{
+	return c.mutate(ctx, map[string]interface{}{
 		"type":        "Delete",
 		"collection":  collection,
 		"document_id": id,
 	})
-	if err != nil {
-		return nil, err
-	}
-	var resp struct{ Documents []Document }
-	json.Unmarshal(result, &resp)
-	if len(resp.Documents) > 0 {
-		return &resp.Documents[0], nil
-	}
-	return nil, nil
 }
 
 // Subscribe to changes
 func (c *Client) Subscribe(ctx context.Context, query string, callback func(ChangeEvent)) (string, error) {
-	result, err := c.send(ctx, map[string]interface{}{"type": "Subscribe", "query": query})
+	return c.subscribe(ctx, map[string]interface{}{"type": "Subscribe", "query": query}, callback)
+}
+
+// Watch subscribes to changes of the documents matching a query built with
+// QueryBuilder, sent in structured form. Changes options set on the builder
+// are kept, e.g. to include the initial results.
+func (c *Client) Watch(ctx context.Context, q *QueryBuilder, callback func(ChangeEvent)) (string, error) {
+	compiled := q.CompileStructured()
+	if compiled.Changes == nil {
+		compiled.Changes = &ChangesOptions{}
+	}
+	return c.subscribe(ctx, map[string]interface{}{"type": "Subscribe", "structured_query": compiled}, callback)
+}
+
+func (c *Client) subscribe(ctx context.Context, msg map[string]interface{}, callback func(ChangeEvent)) (string, error) {
+	result, err := c.send(ctx, msg)
 	if err != nil {
 		return "", err
 	}
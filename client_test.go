@@ -0,0 +1,206 @@
+// SquirrelDB Go SDK - Client Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueryCancellationNotifiesServer(t *testing.T) {
+	srv := newFakeServer(t)
+	stopped := make(chan struct{})
+	srv.handleContext("Query", func(ctx context.Context, msg map[string]interface{}) map[string]interface{} {
+		select {
+		case <-ctx.Done():
+			close(stopped)
+			return nil
+		case <-time.After(5 * time.Second):
+			return documentsReply()
+		}
+	})
+	client := srv.connect(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.Query(ctx, `db.table("events").run()`)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected server to stop the query")
+	}
+
+	queries := srv.messages("Query")
+	cancels := srv.messages("Cancel")
+	if len(cancels) != 1 {
+		t.Fatalf("Expected 1 Cancel message, got %d", len(cancels))
+	}
+	if cancels[0]["request_id"] != queries[0]["id"] {
+		t.Errorf("Expected Cancel for %v, got %v", queries[0]["id"], cancels[0]["request_id"])
+	}
+}
+
+func TestSubscribeCancellationNotifiesServer(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handleContext("Subscribe", func(ctx context.Context, msg map[string]interface{}) map[string]interface{} {
+		<-ctx.Done()
+		return nil
+	})
+	client := srv.connect(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := client.Subscribe(ctx, `db.table("events").changes()`, func(ChangeEvent) {}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context canceled, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(srv.messages("Cancel")) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected Cancel message for subscription request")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCancelledServerErrorMatchesContextCanceled(t *testing.T) {
+	err := &ServerError{Code: CodeCancelled, Message: "request cancelled"}
+	if !errors.Is(err, context.Canceled) {
+		t.Error("Expected cancelled server error to match context.Canceled")
+	}
+	if IsRetryable(err) {
+		t.Error("Expected cancelled server error to be non-retryable")
+	}
+}
+
+func fakeUsers() []Document {
+	return []Document{
+		{Id: "u1", Collection: "users", Data: map[string]interface{}{"name": "Alice"}},
+		{Id: "u2", Collection: "users", Data: map[string]interface{}{"name": "Bob"}},
+		{Id: "u3", Collection: "users", Data: map[string]interface{}{"name": "Carol"}},
+	}
+}
+
+func TestClientGet(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.serveDocuments(fakeUsers()...)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	doc, err := client.Get(ctx, "users", "u2")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if doc.Id != "u2" || doc.Data["name"] != "Bob" {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+
+	_, err = client.Get(ctx, "users", "nope")
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected *NotFoundError, got %v", err)
+	}
+	if notFound.Collection != "users" || notFound.ID != "nope" {
+		t.Errorf("Unexpected not-found error: %+v", notFound)
+	}
+}
+
+func TestClientGetManyPreservesOrder(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.serveDocuments(fakeUsers()...)
+	client := srv.connect(nil)
+
+	docs, err := client.GetMany(context.Background(), "users", []string{"u3", "missing", "u1"})
+	if err != nil {
+		t.Fatalf("GetMany failed: %v", err)
+	}
+	if len(docs) != 2 || docs[0].Id != "u3" || docs[1].Id != "u1" {
+		t.Errorf("Unexpected documents: %+v", docs)
+	}
+	if n := len(srv.messages("Query")); n != 1 {
+		t.Errorf("Expected a single query, got %d", n)
+	}
+}
+
+func TestClientExists(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.serveDocuments(fakeUsers()...)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	if ok, err := client.Exists(ctx, "users", "u1"); err != nil || !ok {
+		t.Errorf("Expected u1 to exist, got %v, %v", ok, err)
+	}
+	if ok, err := client.Exists(ctx, "users", "u9"); err != nil || ok {
+		t.Errorf("Expected u9 not to exist, got %v, %v", ok, err)
+	}
+}
+
+func TestClientFind(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.serveDocuments(fakeUsers()...)
+	client := srv.connect(nil)
+
+	docs, err := client.Find(context.Background(), Table("users").Find(Field("name").Ne("Bob")).Sort("name", SortDesc))
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(docs) != 2 || docs[0].Id != "u3" || docs[1].Id != "u1" {
+		t.Errorf("Unexpected documents: %+v", docs)
+	}
+	msg := srv.messages("Query")[0]
+	if _, hasDSL := msg["query"]; hasDSL {
+		t.Error("Expected structured query only")
+	}
+	q, _ := msg["structured_query"].(map[string]interface{})
+	if q["table"] != "users" || q["filter"] == nil {
+		t.Errorf("Unexpected structured query: %v", q)
+	}
+}
+
+func TestClientWatch(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Subscribe", func(msg map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "Subscribed", "subscription_id": "sub-1"}
+	})
+	client := srv.connect(nil)
+
+	events := make(chan ChangeEvent, 1)
+	id, err := client.Watch(context.Background(), Table("users").Find(Field("age").Gte(18)), func(e ChangeEvent) {
+		events <- e
+	})
+	if err != nil || id != "sub-1" {
+		t.Fatalf("Watch failed: %q, %v", id, err)
+	}
+
+	q, _ := srv.messages("Subscribe")[0]["structured_query"].(map[string]interface{})
+	if q["table"] != "users" || q["filter"] == nil || q["changes"] == nil {
+		t.Errorf("Unexpected structured query: %v", q)
+	}
+
+	srv.push(map[string]interface{}{
+		"type":            "Change",
+		"subscription_id": "sub-1",
+		"change": map[string]interface{}{
+			"type":     "insert",
+			"document": map[string]interface{}{"id": "u1", "collection": "users", "data": map[string]interface{}{"age": 30}},
+		},
+	})
+	select {
+	case e := <-events:
+		if e.Type != "insert" || e.Document == nil || e.Document.Id != "u1" {
+			t.Errorf("Unexpected event: %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected change event")
+	}
+}
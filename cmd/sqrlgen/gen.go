@@ -0,0 +1,381 @@
+// SquirrelDB Go SDK - sqrlgen Code Generation
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	squirreldb "github.com/squirreldb/squirreldb-sdk-go"
+)
+
+// config names the generated code
+type config struct {
+	Package    string
+	Collection string
+	// Type is the document struct, e.g. User; derived from Collection if empty
+	Type string
+	// Var holds the typed fields, e.g. Users; derived from Collection if empty
+	Var string
+	// Source describes where the schema came from, for the header comment
+	Source string
+}
+
+type goField struct {
+	name string
+	// path is the dotted document path; empty for metadata fields other than the id
+	path string
+	typ  string
+	tag  string
+	// expr is the typed field type and ctor its constructor; both are
+	// empty for fields that nest another struct
+	expr, ctor string
+	nested     *goStruct
+}
+
+type goStruct struct {
+	name   string
+	doc    string
+	fields []goField
+}
+
+type generator struct {
+	config
+	structs []*goStruct
+	names   map[string]bool
+}
+
+// generate returns the formatted Go source for the collection schema
+func generate(cfg config, schema *squirreldb.Schema) ([]byte, error) {
+	if cfg.Type == "" {
+		cfg.Type = singular(exportedName(cfg.Collection))
+	}
+	if cfg.Var == "" {
+		cfg.Var = exportedName(cfg.Collection)
+	}
+	if cfg.Type == cfg.Var {
+		cfg.Type += "Doc"
+	}
+	if schema == nil || !hasType(schema, "object") && len(schema.Properties) == 0 {
+		return nil, fmt.Errorf("schema of %s must describe an object", cfg.Collection)
+	}
+
+	g := &generator{config: cfg, names: map[string]bool{cfg.Var: true}}
+	root := g.object(cfg.Type, fmt.Sprintf("%s is a document of the %s collection", cfg.Type, cfg.Collection), schema, "", true)
+
+	var buf bytes.Buffer
+	g.header(&buf)
+	for _, s := range g.structs {
+		g.writeStruct(&buf, s)
+	}
+	g.writeFieldsType(&buf, root)
+	g.writeAccessors(&buf, root)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+// object adds a struct for an object schema, and the structs it nests
+func (g *generator) object(name, doc string, schema *squirreldb.Schema, path string, top bool) *goStruct {
+	s := &goStruct{name: g.unique(name), doc: doc}
+	g.structs = append(g.structs, s)
+	used := make(map[string]bool)
+	add := func(f goField) {
+		base := f.name
+		for i := 2; used[f.name]; i++ {
+			f.name = fmt.Sprintf("%s%d", base, i)
+		}
+		used[f.name] = true
+		s.fields = append(s.fields, f)
+	}
+
+	if top {
+		add(goField{name: "ID", path: "id", typ: "string", tag: `sqrl:"id"`, expr: "squirreldb.StringField", ctor: "squirreldb.NewStringField"})
+	}
+
+	required := make(map[string]bool)
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+	keys := make([]string, 0, len(schema.Properties))
+	for k := range schema.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if top && (key == "id" || key == "created_at" || key == "updated_at") {
+			continue
+		}
+		fieldName := exportedName(key)
+		f := g.field(s.name+fieldName, schema.Properties[key], joinPath(path, key), required[key])
+		f.name = fieldName
+		f.tag = fmt.Sprintf(`sqrl:"%s"`, key)
+		if !required[key] {
+			f.tag = fmt.Sprintf(`sqrl:"%s,omitempty"`, key)
+		}
+		add(f)
+	}
+
+	if top {
+		add(goField{name: "CreatedAt", typ: "time.Time", tag: `sqrl:"created_at"`})
+		add(goField{name: "UpdatedAt", typ: "time.Time", tag: `sqrl:"updated_at"`})
+	}
+	return s
+}
+
+// field maps a property schema to a Go type and a typed field
+func (g *generator) field(name string, schema *squirreldb.Schema, path string, required bool) goField {
+	f := goField{path: path, typ: "interface{}", expr: "*squirreldb.FieldExpr", ctor: "squirreldb.Field"}
+	t, nullable := schemaType(schema)
+	pointer := !required || nullable
+
+	switch t {
+	case "string":
+		f.typ, f.expr, f.ctor = "string", "squirreldb.StringField", "squirreldb.NewStringField"
+		if schema.Format == "date-time" {
+			f.typ, f.expr, f.ctor = "time.Time", "squirreldb.TypedField[time.Time]", "squirreldb.NewField[time.Time]"
+		}
+	case "integer":
+		f.typ, f.expr, f.ctor = "int64", "squirreldb.TypedField[int64]", "squirreldb.NewField[int64]"
+	case "number":
+		f.typ, f.expr, f.ctor = "float64", "squirreldb.TypedField[float64]", "squirreldb.NewField[float64]"
+	case "boolean":
+		f.typ, f.expr, f.ctor = "bool", "squirreldb.TypedField[bool]", "squirreldb.NewField[bool]"
+	case "object":
+		if len(schema.Properties) > 0 {
+			f.nested = g.object(name, fmt.Sprintf("%s is the value of %s", name, path), schema, path, false)
+			f.typ, f.expr, f.ctor = f.nested.name, "", ""
+			break
+		}
+		elem := "interface{}"
+		if schema.AdditionalProperties != nil {
+			elem = g.field(name+"Value", schema.AdditionalProperties, path, true).typ
+		}
+		f.typ, pointer = "map[string]"+elem, false
+	case "array":
+		elem := "interface{}"
+		if schema.Items != nil {
+			itemName := singular(name)
+			if itemName == name {
+				itemName += "Item"
+			}
+			elem = g.field(itemName, schema.Items, path, true).typ
+		}
+		f.typ, pointer = "[]"+elem, false
+	default:
+		pointer = false
+	}
+	if pointer {
+		f.typ = "*" + f.typ
+	}
+	return f
+}
+
+// schemaType returns the single non-null type of a schema, or "" if it
+// allows several, and whether it allows null
+func schemaType(schema *squirreldb.Schema) (string, bool) {
+	if schema == nil {
+		return "", true
+	}
+	types := schema.Type
+	if len(types) == 0 && len(schema.Enum) > 0 {
+		// An untyped enum of strings is still a string
+		allStrings := true
+		for _, e := range schema.Enum {
+			switch e.(type) {
+			case string:
+			case nil:
+				types = append(types, "null")
+			default:
+				allStrings = false
+			}
+		}
+		if allStrings {
+			types = append(types, "string")
+		}
+	}
+	var nonNull []string
+	nullable := false
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+		} else {
+			nonNull = append(nonNull, t)
+		}
+	}
+	if len(nonNull) != 1 {
+		return "", true
+	}
+	return nonNull[0], nullable
+}
+
+func hasType(schema *squirreldb.Schema, want string) bool {
+	for _, t := range schema.Type {
+		if t == want {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) header(buf *bytes.Buffer) {
+	fmt.Fprintf(buf, "// Code generated by sqrlgen from %s. DO NOT EDIT.\n\n", g.Source)
+	fmt.Fprintf(buf, "package %s\n\n", g.Package)
+	buf.WriteString("import (\n\t\"context\"\n\t\"time\"\n\n\tsquirreldb \"github.com/squirreldb/squirreldb-sdk-go\"\n)\n\n")
+}
+
+func (g *generator) writeStruct(buf *bytes.Buffer, s *goStruct) {
+	if s.doc != "" {
+		fmt.Fprintf(buf, "// %s\n", s.doc)
+	}
+	fmt.Fprintf(buf, "type %s struct {\n", s.name)
+	for _, f := range s.fields {
+		fmt.Fprintf(buf, "\t%s %s `%s`\n", f.name, f.typ, f.tag)
+	}
+	buf.WriteString("}\n\n")
+}
+
+// fieldsType names the struct of typed fields for a document struct
+func fieldsType(s *goStruct) string {
+	r := []rune(s.name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r) + "Fields"
+}
+
+// writeFieldsType writes the struct of typed fields for s and the structs
+// it nests; structs inside arrays and maps have no typed fields
+func (g *generator) writeFieldsType(buf *bytes.Buffer, s *goStruct) {
+	fmt.Fprintf(buf, "type %s struct {\n", fieldsType(s))
+	for _, f := range s.fields {
+		switch {
+		case f.nested != nil:
+			fmt.Fprintf(buf, "\t%s %s\n", f.name, fieldsType(f.nested))
+		case f.path != "":
+			fmt.Fprintf(buf, "\t%s %s\n", f.name, f.expr)
+		}
+	}
+	buf.WriteString("}\n\n")
+	for _, f := range s.fields {
+		if f.nested != nil {
+			g.writeFieldsType(buf, f.nested)
+		}
+	}
+}
+
+// writeFieldsValue writes the composite literal of the typed fields of s
+func writeFieldsValue(buf *bytes.Buffer, s *goStruct, indent string) {
+	fmt.Fprintf(buf, "%s{\n", fieldsType(s))
+	for _, f := range s.fields {
+		switch {
+		case f.nested != nil:
+			fmt.Fprintf(buf, "%s\t%s: ", indent, f.name)
+			writeFieldsValue(buf, f.nested, indent+"\t")
+			buf.WriteString(",\n")
+		case f.path != "":
+			fmt.Fprintf(buf, "%s\t%s: %s(%q),\n", indent, f.name, f.ctor, f.path)
+		}
+	}
+	fmt.Fprintf(buf, "%s}", indent)
+}
+
+func (g *generator) writeAccessors(buf *bytes.Buffer, root *goStruct) {
+	v, t, c := g.Var, g.Type, g.Collection
+	fmt.Fprintf(buf, "// %sCollection is the name of the %s collection\n", v, c)
+	fmt.Fprintf(buf, "const %sCollection = %q\n\n", v, c)
+
+	fmt.Fprintf(buf, "// %s holds the typed fields of the %s collection for building filters,\n", v, c)
+	fmt.Fprintf(buf, "// e.g. %sQuery(%s.ID.Eq(id))\n", v, v)
+	fmt.Fprintf(buf, "var %s = ", v)
+	writeFieldsValue(buf, root, "")
+	buf.WriteString("\n\n")
+
+	fmt.Fprintf(buf, "// %sQuery starts a query on the %s collection\n", v, c)
+	fmt.Fprintf(buf, "func %sQuery(conditions ...squirreldb.FilterCondition) *squirreldb.QueryBuilder {\n", v)
+	fmt.Fprintf(buf, "\treturn squirreldb.Table(%sCollection).Find(conditions...)\n}\n\n", v)
+
+	fmt.Fprintf(buf, "// New%s returns the typed %s collection\n", v, c)
+	fmt.Fprintf(buf, "func New%s(client *squirreldb.Client) *squirreldb.Collection[%s] {\n", v, t)
+	fmt.Fprintf(buf, "\treturn squirreldb.NewCollection[%s](client, %sCollection)\n}\n\n", t, v)
+
+	fmt.Fprintf(buf, "// Find%s returns the %s documents matching all conditions\n", v, c)
+	fmt.Fprintf(buf, "func Find%s(ctx context.Context, client *squirreldb.Client, conditions ...squirreldb.FilterCondition) ([]%s, error) {\n", v, t)
+	fmt.Fprintf(buf, "\treturn New%s(client).Find(ctx, %sQuery(conditions...))\n}\n\n", v, v)
+
+	fmt.Fprintf(buf, "// Get%s returns the %s document with the given id\n", t, c)
+	fmt.Fprintf(buf, "func Get%s(ctx context.Context, client *squirreldb.Client, id string) (%s, error) {\n", t, t)
+	fmt.Fprintf(buf, "\treturn New%s(client).Get(ctx, id)\n}\n", v)
+}
+
+// unique returns name, numbered if another generated type already uses it
+func (g *generator) unique(name string) string {
+	base := name
+	for i := 2; g.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.names[name] = true
+	return name
+}
+
+var initialisms = map[string]bool{
+	"API": true, "DB": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "SQL": true, "TTL": true, "URI": true, "URL": true,
+	"UUID": true, "XML": true,
+}
+
+// exportedName converts a document field name such as org_id or createdAt
+// into an exported Go identifier
+func exportedName(key string) string {
+	parts := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, p := range parts {
+		if initialisms[strings.ToUpper(p)] {
+			b.WriteString(strings.ToUpper(p))
+			continue
+		}
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	name := b.String()
+	if name == "" {
+		return "Field"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "F" + name
+	}
+	return name
+}
+
+// singular guesses the singular of an English plural, e.g. for the
+// document type of a collection
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"),
+		strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "ss"), strings.HasSuffix(name, "us"):
+		return name
+	case strings.HasSuffix(name, "s") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,141 @@
+// SquirrelDB Go SDK - sqrlgen Code Generation Tests
+
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	squirreldb "github.com/squirreldb/squirreldb-sdk-go"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["email", "address"],
+	"properties": {
+		"id": {"type": "string"},
+		"email": {"type": "string", "format": "email"},
+		"age": {"type": "integer"},
+		"score": {"type": ["number", "null"]},
+		"role": {"enum": ["admin", "member"]},
+		"org_id": {"type": "string"},
+		"last_login": {"type": "string", "format": "date-time"},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"items": {"type": "array", "items": {"type": "object", "properties": {"sku": {"type": "string"}}}},
+		"address": {"type": "object", "required": ["city"], "properties": {"city": {"type": "string"}}},
+		"meta": {}
+	}
+}`
+
+func generateTest(t *testing.T, cfg config, src string) string {
+	t.Helper()
+	schema, err := squirreldb.ParseSchema([]byte(src))
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+	out, err := generate(cfg, schema)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	return string(out)
+}
+
+// squash collapses whitespace so expectations do not depend on gofmt alignment
+func squash(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func TestGenerate(t *testing.T) {
+	src := squash(generateTest(t, config{Package: "models", Collection: "users", Source: "users.json"}, testSchema))
+
+	for _, want := range []string{
+		"// Code generated by sqrlgen from users.json. DO NOT EDIT.",
+		"package models",
+		"type User struct {",
+		"ID string `sqrl:\"id\"`",
+		"Email string `sqrl:\"email\"`",
+		"Age *int64 `sqrl:\"age,omitempty\"`",
+		"Score *float64 `sqrl:\"score,omitempty\"`",
+		"Role *string `sqrl:\"role,omitempty\"`",
+		"LastLogin *time.Time `sqrl:\"last_login,omitempty\"`",
+		"Tags []string `sqrl:\"tags,omitempty\"`",
+		"Items []UserItem `sqrl:\"items,omitempty\"`",
+		"Address UserAddress `sqrl:\"address\"`",
+		"Meta interface{} `sqrl:\"meta,omitempty\"`",
+		"City string `sqrl:\"city\"`",
+		"Sku *string `sqrl:\"sku,omitempty\"`",
+		"OrgID: squirreldb.NewStringField(\"org_id\"),",
+		"Age: squirreldb.NewField[int64](\"age\"),",
+		"City: squirreldb.NewStringField(\"address.city\"),",
+		"Tags: squirreldb.Field(\"tags\"),",
+		"const UsersCollection = \"users\"",
+		"func UsersQuery(conditions ...squirreldb.FilterCondition) *squirreldb.QueryBuilder {",
+		"func NewUsers(client *squirreldb.Client) *squirreldb.Collection[User] {",
+		"func FindUsers(ctx context.Context, client *squirreldb.Client, conditions ...squirreldb.FilterCondition) ([]User, error) {",
+		"func GetUser(ctx context.Context, client *squirreldb.Client, id string) (User, error) {",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Expected generated code to contain %q\n%s", want, src)
+		}
+	}
+	if strings.Count(src, "`sqrl:\"id\"`") != 1 {
+		t.Errorf("Expected the id property to map to the document id only once\n%s", src)
+	}
+	if strings.Contains(src, "userItemFields") {
+		t.Errorf("Expected no typed fields for structs inside arrays\n%s", src)
+	}
+}
+
+// TestGeneratedCodeTypeChecks compiles the generated code against the SDK,
+// including a use of the typed fields
+func TestGeneratedCodeTypeChecks(t *testing.T) {
+	src := generateTest(t, config{Package: "models", Collection: "users", Source: "users.json"}, testSchema)
+	src += `
+var _ = UsersQuery(Users.Email.EndsWith("@example.com"), Users.Age.Gte(18), Users.Address.City.Eq("Oslo")).Sort(Users.Email.Name(), squirreldb.SortAsc)
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "users_gen.go", src, 0)
+	if err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, src)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("models", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("Generated code does not type check: %v\n%s", err, src)
+	}
+}
+
+func TestGenerateNames(t *testing.T) {
+	src := squash(generateTest(t, config{Package: "models", Collection: "sheep"}, `{"type": "object", "properties": {"1st-place": {"type": "boolean"}, "api_url": {"type": "string"}}}`))
+	for _, want := range []string{"type SheepDoc struct", "var Sheep = sheepDocFields{", "F1stPlace *bool", "APIURL *string"} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Expected generated code to contain %q\n%s", want, src)
+		}
+	}
+
+	if _, err := generate(config{Package: "models", Collection: "users"}, &squirreldb.Schema{Type: squirreldb.SchemaType{"array"}}); err == nil {
+		t.Error("Expected error for a schema that is not an object")
+	}
+}
+
+func TestNameHelpers(t *testing.T) {
+	for in, want := range map[string]string{
+		"users": "User", "categories": "Category", "addresses": "Address",
+		"boxes": "Box", "status": "Status", "class": "Class",
+	} {
+		if got := singular(exportedName(in)); got != want {
+			t.Errorf("singular(%s): expected %s, got %s", in, want, got)
+		}
+	}
+	for in, want := range map[string]string{
+		"org_id": "OrgID", "createdAt": "CreatedAt", "user-name": "UserName", "http_status": "HTTPStatus", "": "Field",
+	} {
+		if got := exportedName(in); got != want {
+			t.Errorf("exportedName(%q): expected %s, got %s", in, want, got)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// SquirrelDB Go SDK - sqrlgen Schema Inference
+
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	squirreldb "github.com/squirreldb/squirreldb-sdk-go"
+)
+
+// inferSchema derives a schema from sample documents. A field is required
+// if every sampled document has it, and strings are date-times if every
+// sampled value parses as one.
+func inferSchema(docs []squirreldb.Document) *squirreldb.Schema {
+	values := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		data := doc.Data
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+		values[i] = data
+	}
+	return infer(values)
+}
+
+// infer derives the schema of the decoded JSON values of one field
+func infer(values []interface{}) *squirreldb.Schema {
+	s := &squirreldb.Schema{}
+	types := make(map[string]bool)
+	var objects []map[string]interface{}
+	var items []interface{}
+	dates := true
+
+	for _, v := range values {
+		switch val := v.(type) {
+		case nil:
+			types["null"] = true
+		case bool:
+			types["boolean"] = true
+		case float64:
+			if val == math.Trunc(val) && !math.IsInf(val, 0) {
+				types["integer"] = true
+			} else {
+				types["number"] = true
+			}
+		case string:
+			types["string"] = true
+			if _, err := time.Parse(time.RFC3339Nano, val); err != nil {
+				dates = false
+			}
+		case []interface{}:
+			types["array"] = true
+			items = append(items, val...)
+		case map[string]interface{}:
+			types["object"] = true
+			objects = append(objects, val)
+		}
+	}
+	if types["integer"] && types["number"] {
+		delete(types, "integer")
+	}
+	for t := range types {
+		s.Type = append(s.Type, t)
+	}
+	sort.Strings(s.Type)
+
+	if types["string"] && dates {
+		s.Format = "date-time"
+	}
+	if types["array"] && len(items) > 0 {
+		s.Items = infer(items)
+	}
+	if types["object"] {
+		fields := make(map[string][]interface{})
+		for _, obj := range objects {
+			for k, v := range obj {
+				fields[k] = append(fields[k], v)
+			}
+		}
+		s.Properties = make(map[string]*squirreldb.Schema, len(fields))
+		for k, vals := range fields {
+			s.Properties[k] = infer(vals)
+			if len(vals) == len(objects) {
+				s.Required = append(s.Required, k)
+			}
+		}
+		sort.Strings(s.Required)
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+// SquirrelDB Go SDK - sqrlgen Schema Inference Tests
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	squirreldb "github.com/squirreldb/squirreldb-sdk-go"
+)
+
+func TestInferSchema(t *testing.T) {
+	docs := []squirreldb.Document{
+		{Id: "1", Data: map[string]interface{}{
+			"email":      "ann@example.com",
+			"age":        float64(30),
+			"score":      1.5,
+			"last_login": "2024-05-01T10:00:00Z",
+			"tags":       []interface{}{"a", "b"},
+			"address":    map[string]interface{}{"city": "Oslo", "zip": "0150"},
+			"nickname":   nil,
+		}},
+		{Id: "2", Data: map[string]interface{}{
+			"email":      "bob@example.com",
+			"age":        float64(41),
+			"score":      float64(2),
+			"last_login": "never",
+			"address":    map[string]interface{}{"city": "Bergen"},
+			"nickname":   "bobby",
+		}},
+	}
+	s := inferSchema(docs)
+
+	if !reflect.DeepEqual(s.Type, squirreldb.SchemaType{"object"}) {
+		t.Errorf("Expected object root, got %v", s.Type)
+	}
+	if got := strings.Join(s.Required, ","); got != "address,age,email,last_login,nickname,score" {
+		t.Errorf("Unexpected required fields: %s", got)
+	}
+	for field, want := range map[string]squirreldb.SchemaType{
+		"age":      {"integer"},
+		"score":    {"number"},
+		"nickname": {"null", "string"},
+		"tags":     {"array"},
+	} {
+		if got := s.Properties[field].Type; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected type %v, got %v", field, want, got)
+		}
+	}
+	if s.Properties["last_login"].Format != "" {
+		t.Error("Expected no date-time format when a sample is not a date")
+	}
+	if got := s.Properties["tags"].Items.Type; !reflect.DeepEqual(got, squirreldb.SchemaType{"string"}) {
+		t.Errorf("Expected string items, got %v", got)
+	}
+	address := s.Properties["address"]
+	if !reflect.DeepEqual(address.Required, []string{"city"}) || address.Properties["zip"] == nil {
+		t.Errorf("Unexpected nested schema: %+v", address)
+	}
+
+	src, err := generate(config{Package: "models", Collection: "users", Source: "samples"}, s)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	for _, want := range []string{"Nickname *string", "Age int64", "Zip *string"} {
+		if !strings.Contains(squash(string(src)), want) {
+			t.Errorf("Expected generated code to contain %q\n%s", want, src)
+		}
+	}
+}
+
+func TestInferDateTime(t *testing.T) {
+	s := infer([]interface{}{"2024-05-01T10:00:00Z", "2024-05-02T11:30:00.5+02:00"})
+	if s.Format != "date-time" {
+		t.Errorf("Expected date-time format, got %q", s.Format)
+	}
+}
@@ -0,0 +1,110 @@
+// Command sqrlgen generates typed Go accessors for a SquirrelDB collection:
+// a document struct, typed field constants for filters and query helpers.
+//
+// The collection shape is read from a JSON Schema file, such as one written
+// for Client.PushSchema, or inferred by sampling documents of the live
+// collection:
+//
+//	sqrlgen -collection users -schema users.schema.json -o users_gen.go
+//	sqrlgen -collection users -sample 500 -host localhost -port 8080 -o users_gen.go
+//
+// For a users collection it emits a User struct, a Users variable whose
+// fields build filters that are checked at compile time, e.g.
+//
+//	FindUsers(ctx, client, Users.Email.Eq("ann@example.com"), Users.Age.Gte(18))
+//
+// and the UsersQuery, NewUsers, FindUsers and GetUser helpers. Under
+// go:generate the package defaults to $GOPACKAGE.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	squirreldb "github.com/squirreldb/squirreldb-sdk-go"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "sqrlgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		cfg        config
+		schemaFile = flag.String("schema", "", "JSON Schema file describing the collection documents")
+		sample     = flag.Int("sample", 100, "number of documents to sample when no -schema is given")
+		host       = flag.String("host", "localhost", "server host, for sampling")
+		port       = flag.Int("port", 8080, "server port, for sampling")
+		token      = flag.String("token", os.Getenv("SQUIRRELDB_TOKEN"), "auth token, for sampling")
+		timeout    = flag.Duration("timeout", 30*time.Second, "timeout for sampling")
+		out        = flag.String("o", "", "output file; defaults to standard output")
+	)
+	flag.StringVar(&cfg.Collection, "collection", "", "collection name (required)")
+	flag.StringVar(&cfg.Package, "package", os.Getenv("GOPACKAGE"), "package of the generated file")
+	flag.StringVar(&cfg.Type, "type", "", "document struct name; defaults to the singular of the collection")
+	flag.StringVar(&cfg.Var, "var", "", "typed fields variable name; defaults to the collection")
+	flag.Parse()
+
+	if cfg.Collection == "" {
+		return errors.New("-collection is required")
+	}
+	if cfg.Package == "" {
+		return errors.New("-package is required outside go:generate")
+	}
+
+	var schema *squirreldb.Schema
+	if *schemaFile != "" {
+		data, err := os.ReadFile(*schemaFile)
+		if err != nil {
+			return err
+		}
+		if schema, err = squirreldb.ParseSchema(data); err != nil {
+			return fmt.Errorf("%s: %w", *schemaFile, err)
+		}
+		cfg.Source = *schemaFile
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		docs, err := sampleDocuments(ctx, &squirreldb.Options{Host: *host, Port: *port, AuthToken: *token}, cfg.Collection, *sample)
+		if err != nil {
+			return err
+		}
+		schema = inferSchema(docs)
+		cfg.Source = fmt.Sprintf("%d sampled %s documents", len(docs), cfg.Collection)
+	}
+
+	src, err := generate(cfg, schema)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0o644)
+}
+
+// sampleDocuments reads up to n documents of the collection
+func sampleDocuments(ctx context.Context, opts *squirreldb.Options, collection string, n int) ([]squirreldb.Document, error) {
+	client, err := squirreldb.Connect(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	docs, err := client.Find(ctx, squirreldb.Table(collection).Limit(n))
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("collection %s has no documents to sample; use -schema", collection)
+	}
+	return docs, nil
+}
@@ -0,0 +1,184 @@
+// SquirrelDB Go SDK - Typed Collections
+
+package squirreldb
+
+import (
+	"context"
+	"fmt"
+)
+
+// Collection provides typed access to a collection, converting between
+// documents and values of type T using the sqrl struct tag mapping
+type Collection[T any] struct {
+	client *Client
+	name   string
+}
+
+// TypedChangeEvent is a ChangeEvent whose documents are decoded into T
+type TypedChangeEvent[T any] struct {
+	Type ChangeType
+	// Id is the id of the changed document
+	Id       string
+	Document *T
+	Old      *T
+	New      *T
+	// Raw is the undecoded event
+	Raw ChangeEvent
+}
+
+// NewCollection returns a typed view of the named collection
+func NewCollection[T any](client *Client, name string) *Collection[T] {
+	return &Collection[T]{client: client, name: name}
+}
+
+// Name returns the collection name
+func (c *Collection[T]) Name() string {
+	return c.name
+}
+
+// Insert a value and return it as stored
+func (c *Collection[T]) Insert(ctx context.Context, v T) (T, error) {
+	data, err := c.toData(v)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	doc, err := c.client.Insert(ctx, c.name, data)
+	return c.result(doc, err)
+}
+
+// Get a value by document id, returning a *NotFoundError if it does not exist
+func (c *Collection[T]) Get(ctx context.Context, id string) (T, error) {
+	doc, err := c.client.Get(ctx, c.name, id)
+	return c.result(doc, err)
+}
+
+// Update the document with the given id and return it as stored
+func (c *Collection[T]) Update(ctx context.Context, id string, v T) (T, error) {
+	data, err := c.toData(v)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	doc, err := c.client.Update(ctx, c.name, id, data)
+	return c.result(doc, err)
+}
+
+// Modify reads the document, lets fn change it and writes it back unless
+// it was changed concurrently, see Client.Modify
+func (c *Collection[T]) Modify(ctx context.Context, id string, fn func(v *T) error) (T, error) {
+	versioned, err := c.client.Modify(ctx, c.name, id, func(doc *Document) error {
+		v, err := c.decode(doc)
+		if err != nil {
+			return err
+		}
+		if err := fn(&v); err != nil {
+			return err
+		}
+		data, err := c.toData(v)
+		if err != nil {
+			return err
+		}
+		doc.Data = data
+		return nil
+	})
+	var doc *Document
+	if versioned != nil {
+		doc = &versioned.Document
+	}
+	return c.result(doc, err)
+}
+
+// Delete the document with the given id
+func (c *Collection[T]) Delete(ctx context.Context, id string) error {
+	_, err := c.client.Delete(ctx, c.name, id)
+	return err
+}
+
+// Find runs a query against the collection; the builder's table name is
+// replaced with the collection name and a nil builder matches everything
+func (c *Collection[T]) Find(ctx context.Context, q *QueryBuilder) ([]T, error) {
+	docs, err := c.client.Find(ctx, c.query(q))
+	if err != nil {
+		return nil, err
+	}
+	values := make([]T, len(docs))
+	for i := range docs {
+		if values[i], err = c.decode(&docs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// query targets q at the collection, or matches everything if q is nil
+func (c *Collection[T]) query(q *QueryBuilder) *QueryBuilder {
+	if q == nil {
+		return Table(c.name)
+	}
+	q = q.clone()
+	q.tableName = c.name
+	return q
+}
+
+// Subscribe to changes in the collection. Events whose documents cannot be
+// decoded into T are delivered with only Type, Id and Raw set.
+func (c *Collection[T]) Subscribe(ctx context.Context, callback func(TypedChangeEvent[T])) (string, error) {
+	return c.Watch(ctx, nil, callback)
+}
+
+// Watch subscribes to changes of the documents matching q, see Find and Subscribe
+func (c *Collection[T]) Watch(ctx context.Context, q *QueryBuilder, callback func(TypedChangeEvent[T])) (string, error) {
+	return c.client.Watch(ctx, c.query(q), func(change ChangeEvent) {
+		callback(c.decodeEvent(change))
+	})
+}
+
+func (c *Collection[T]) decodeEvent(change ChangeEvent) TypedChangeEvent[T] {
+	event := TypedChangeEvent[T]{Type: change.Type, Raw: change}
+	decodeDoc := func(doc *Document) *T {
+		if doc == nil {
+			return nil
+		}
+		if event.Id == "" {
+			event.Id = doc.Id
+		}
+		v, err := c.decode(doc)
+		if err != nil {
+			return nil
+		}
+		return &v
+	}
+	event.Document = decodeDoc(change.Document)
+	event.New = decodeDoc(change.New)
+	if old, err := change.OldDocument(); err == nil {
+		event.Old = decodeDoc(old)
+	}
+	return event
+}
+
+func (c *Collection[T]) result(doc *Document, err error) (T, error) {
+	var zero T
+	if err != nil || doc == nil {
+		return zero, err
+	}
+	return c.decode(doc)
+}
+
+// toData converts a value into document data using its sqrl tags
+func (c *Collection[T]) toData(v T) (map[string]interface{}, error) {
+	data, err := ToData(v)
+	if err != nil {
+		return nil, fmt.Errorf("collection %s: %w", c.name, err)
+	}
+	return data, nil
+}
+
+// decode converts a document into T, binding its id and timestamps
+func (c *Collection[T]) decode(doc *Document) (T, error) {
+	var v T
+	if err := FromDocument(doc, &v); err != nil {
+		return v, fmt.Errorf("collection %s: decode document %s: %w", c.name, doc.Id, err)
+	}
+	return v, nil
+}
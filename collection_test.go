@@ -0,0 +1,117 @@
+// SquirrelDB Go SDK - Typed Collection Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testUser struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Age       int       `json:"age"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func TestCollectionInsertAndGet(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Insert", func(msg map[string]interface{}) map[string]interface{} {
+		data := msg["data"].(map[string]interface{})
+		return documentsReply(map[string]interface{}{
+			"id":         "u1",
+			"collection": msg["collection"],
+			"data":       data,
+			"created_at": "2024-01-01T00:00:00Z",
+		})
+	})
+	srv.handle("Query", func(msg map[string]interface{}) map[string]interface{} {
+		q := msg["structured_query"].(map[string]interface{})
+		filter := q["filter"].(map[string]interface{})
+		if filter["id"].(map[string]interface{})["$eq"] != "u1" {
+			return documentsReply()
+		}
+		return documentsReply(map[string]interface{}{
+			"id":   "u1",
+			"data": map[string]interface{}{"name": "Alice", "age": 30},
+		})
+	})
+	users := NewCollection[testUser](srv.connect(nil), "users")
+	ctx := context.Background()
+
+	inserted, err := users.Insert(ctx, testUser{ID: "ignored", Name: "Alice", Age: 30})
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if inserted.ID != "u1" || inserted.Name != "Alice" {
+		t.Errorf("Unexpected inserted value: %+v", inserted)
+	}
+	if !inserted.CreatedAt.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected created_at to be decoded, got %v", inserted.CreatedAt)
+	}
+	sent := srv.messages("Insert")[0]["data"].(map[string]interface{})
+	if _, ok := sent["id"]; ok {
+		t.Error("Expected id to be excluded from inserted data")
+	}
+
+	got, err := users.Get(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Age != 30 {
+		t.Errorf("Expected age 30, got %d", got.Age)
+	}
+
+	if _, err := users.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCollectionFindUsesCollectionName(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Query", func(msg map[string]interface{}) map[string]interface{} {
+		return documentsReply(
+			map[string]interface{}{"id": "u1", "data": map[string]interface{}{"name": "Alice"}},
+			map[string]interface{}{"id": "u2", "data": map[string]interface{}{"name": "Bob"}},
+		)
+	})
+	users := NewCollection[testUser](srv.connect(nil), "users")
+
+	q := Table("other").Find(Field("age").Gte(18))
+	found, err := users.Find(context.Background(), q)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(found) != 2 || found[1].ID != "u2" || found[1].Name != "Bob" {
+		t.Errorf("Unexpected results: %+v", found)
+	}
+	sent := srv.messages("Query")[0]["structured_query"].(map[string]interface{})
+	if sent["table"] != "users" {
+		t.Errorf("Expected table 'users', got %v", sent["table"])
+	}
+	if q.tableName != "other" {
+		t.Error("Expected caller's builder to be left unchanged")
+	}
+}
+
+func TestCollectionDecodeEvent(t *testing.T) {
+	users := NewCollection[testUser](nil, "users")
+	var old interface{} = map[string]interface{}{"id": "u1", "data": map[string]interface{}{"name": "Alice"}}
+	event := users.decodeEvent(ChangeEvent{
+		Type: ChangeTypeUpdate,
+		Old:  &old,
+		New:  &Document{Id: "u1", Data: map[string]interface{}{"name": "Alicia"}},
+	})
+
+	if event.Id != "u1" {
+		t.Errorf("Expected id 'u1', got '%s'", event.Id)
+	}
+	if event.Old == nil || event.Old.Name != "Alice" {
+		t.Errorf("Expected old name 'Alice', got %+v", event.Old)
+	}
+	if event.New == nil || event.New.Name != "Alicia" {
+		t.Errorf("Expected new name 'Alicia', got %+v", event.New)
+	}
+}
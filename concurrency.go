@@ -0,0 +1,181 @@
+// SquirrelDB Go SDK - Optimistic Concurrency
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Precondition is the state a document must still be in for a conditional
+// write to apply. Revision takes precedence over UpdatedAt when both are set.
+type Precondition struct {
+	Revision  int64
+	UpdatedAt string
+}
+
+// VersionedDocument is a document with the revision the server reported
+// for it, as read by GetVersioned and returned by conditional writes
+type VersionedDocument struct {
+	Document
+	// Revision increases with every write of the document; 0 if the server
+	// does not track revisions
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// GetVersioned gets a document by id with its revision, returning a
+// *NotFoundError if it does not exist
+func (c *Client) GetVersioned(ctx context.Context, collection, id string) (*VersionedDocument, error) {
+	result, err := c.request(ctx, map[string]interface{}{
+		"type":             "Query",
+		"structured_query": Table(collection).Find(Field("id").Eq(id)).Limit(1).CompileStructured(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	doc := firstVersioned(result)
+	if doc == nil {
+		return nil, &NotFoundError{Collection: collection, ID: id}
+	}
+	return doc, nil
+}
+
+// firstVersioned decodes the first document of a Result message, if any
+func firstVersioned(result json.RawMessage) *VersionedDocument {
+	var resp struct{ Documents []VersionedDocument }
+	json.Unmarshal(result, &resp)
+	if len(resp.Documents) > 0 {
+		return &resp.Documents[0]
+	}
+	return nil
+}
+
+// IfRevision requires the document to be at the given revision
+func IfRevision(revision int64) Precondition {
+	return Precondition{Revision: revision}
+}
+
+// IfUpdatedAt requires the document's updated_at to still be the one doc
+// was read with. The server compares the timestamp as a string, so it is
+// sent exactly as received rather than re-formatted.
+func IfUpdatedAt(doc *Document) Precondition {
+	return Precondition{UpdatedAt: doc.UpdatedAt}
+}
+
+// IfUnchanged requires the document not to have changed since doc was read,
+// using its revision if the server reported one and updated_at otherwise
+func IfUnchanged(doc *VersionedDocument) Precondition {
+	if doc.Revision != 0 {
+		return Precondition{Revision: doc.Revision}
+	}
+	return IfUpdatedAt(&doc.Document)
+}
+
+func (p Precondition) wire() (map[string]interface{}, error) {
+	switch {
+	case p.Revision != 0:
+		return map[string]interface{}{"revision": p.Revision}, nil
+	case p.UpdatedAt != "":
+		return map[string]interface{}{"updated_at": p.UpdatedAt}, nil
+	}
+	return nil, errors.New("precondition: no revision or updated_at")
+}
+
+func (p Precondition) String() string {
+	if p.Revision != 0 {
+		return fmt.Sprintf("revision %d", p.Revision)
+	}
+	return "updated_at " + p.UpdatedAt
+}
+
+// ConflictError is returned by conditional writes whose precondition no
+// longer holds because the document was changed concurrently
+type ConflictError struct {
+	Collection   string
+	ID           string
+	Precondition Precondition
+	Err          error
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("document %s/%s no longer at %s", e.Collection, e.ID, e.Precondition)
+}
+
+// Is matches ErrConflict
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// UpdateIf replaces the data of a document only if it satisfies the
+// precondition, returning a *ConflictError otherwise
+func (c *Client) UpdateIf(ctx context.Context, collection, id string, data map[string]interface{}, pre Precondition) (*VersionedDocument, error) {
+	return c.conditional(ctx, pre, map[string]interface{}{
+		"type":        "Update",
+		"collection":  collection,
+		"document_id": id,
+		"data":        data,
+	})
+}
+
+// DeleteIf deletes a document only if it satisfies the precondition,
+// returning a *ConflictError otherwise
+func (c *Client) DeleteIf(ctx context.Context, collection, id string, pre Precondition) (*VersionedDocument, error) {
+	return c.conditional(ctx, pre, map[string]interface{}{
+		"type":        "Delete",
+		"collection":  collection,
+		"document_id": id,
+	})
+}
+
+func (c *Client) conditional(ctx context.Context, pre Precondition, msg map[string]interface{}) (*VersionedDocument, error) {
+	ifMatch, err := pre.wire()
+	if err != nil {
+		return nil, err
+	}
+	msg["if_match"] = ifMatch
+	result, err := c.write(ctx, msg)
+	var serverErr *ServerError
+	if errors.As(err, &serverErr) && serverErr.Code == CodeConflict {
+		return nil, &ConflictError{
+			Collection:   msg["collection"].(string),
+			ID:           msg["document_id"].(string),
+			Precondition: pre,
+			Err:          err,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return firstVersioned(result), nil
+}
+
+// Modify performs a read-modify-write of a document: it reads the document,
+// lets fn change its Data and writes it back only if it was not changed in
+// the meantime. On conflict the whole cycle is repeated, up to
+// Options.TransactionAttempts times, so fn must not have other side effects.
+// An error returned by fn aborts without writing.
+func (c *Client) Modify(ctx context.Context, collection, id string, fn func(doc *Document) error) (*VersionedDocument, error) {
+	for attempt := 1; ; attempt++ {
+		doc, err := c.GetVersioned(ctx, collection, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := fn(&doc.Document); err != nil {
+			return nil, err
+		}
+		updated, err := c.UpdateIf(ctx, collection, id, doc.Data, IfUnchanged(doc))
+		if err == nil || attempt >= c.txAttempts || !errors.Is(err, ErrConflict) {
+			return updated, err
+		}
+
+		if err := txWait(ctx, attempt, err); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// SquirrelDB Go SDK - Optimistic Concurrency Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestUpdateIfRevision(t *testing.T) {
+	srv := newFakeServer(t)
+	st := srv.serveStore(fakeAccounts()...)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	doc, err := client.GetVersioned(ctx, "accounts", "alice")
+	if err != nil {
+		t.Fatalf("GetVersioned failed: %v", err)
+	}
+	if doc.Revision != 1 {
+		t.Fatalf("Expected revision 1, got %d", doc.Revision)
+	}
+
+	updated, err := client.UpdateIf(ctx, "accounts", "alice", map[string]interface{}{"credits": 90.0}, IfUnchanged(doc))
+	if err != nil {
+		t.Fatalf("UpdateIf failed: %v", err)
+	}
+	if updated.Revision != 2 {
+		t.Errorf("Expected revision 2, got %d", updated.Revision)
+	}
+
+	_, err = client.UpdateIf(ctx, "accounts", "alice", map[string]interface{}{"credits": 0.0}, IfRevision(1))
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected *ConflictError, got %v", err)
+	}
+	if conflict.ID != "alice" || conflict.Precondition.Revision != 1 {
+		t.Errorf("Unexpected conflict: %+v", conflict)
+	}
+	if credits(t, st, "alice") != 90.0 {
+		t.Errorf("Expected stale write to be rejected, got %v", credits(t, st, "alice"))
+	}
+
+	msg := srv.messages("Update")[0]
+	ifMatch, _ := msg["if_match"].(map[string]interface{})
+	if ifMatch["revision"] != float64(1) {
+		t.Errorf("Expected if_match revision 1, got %v", msg["if_match"])
+	}
+}
+
+func TestDeleteIf(t *testing.T) {
+	srv := newFakeServer(t)
+	st := srv.serveStore(fakeAccounts()...)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	if _, err := client.DeleteIf(ctx, "accounts", "bob", IfRevision(7)); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected conflict, got %v", err)
+	}
+	if _, err := client.DeleteIf(ctx, "accounts", "bob", IfRevision(1)); err != nil {
+		t.Fatalf("DeleteIf failed: %v", err)
+	}
+	if _, ok := st.get("accounts", "bob"); ok {
+		t.Error("Expected bob to be deleted")
+	}
+	if _, err := client.DeleteIf(ctx, "accounts", "alice", Precondition{}); err == nil {
+		t.Error("Expected error for empty precondition")
+	}
+}
+
+func TestIfUpdatedAtSendsRawTimestamp(t *testing.T) {
+	doc := &Document{Id: "alice", UpdatedAt: "2024-05-01T10:00:00.000+00:00"}
+	ifMatch, err := IfUpdatedAt(doc).wire()
+	if err != nil {
+		t.Fatalf("wire failed: %v", err)
+	}
+	if ifMatch["updated_at"] != doc.UpdatedAt {
+		t.Errorf("Expected the timestamp as read, got %v", ifMatch["updated_at"])
+	}
+}
+
+func TestModifyRetriesOnConflict(t *testing.T) {
+	srv := newFakeServer(t)
+	st := srv.serveStore(fakeAccounts()...)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	attempts := 0
+	doc, err := client.Modify(ctx, "accounts", "alice", func(doc *Document) error {
+		attempts++
+		if attempts == 1 {
+			// A concurrent writer gets in between the read and the write
+			if _, err := client.Update(ctx, "accounts", "alice", map[string]interface{}{"credits": 50.0}); err != nil {
+				return err
+			}
+		}
+		return doc.Set("credits", doc.FloatOr("credits", 0)+5)
+	})
+	if err != nil {
+		t.Fatalf("Modify failed: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+	if doc.Data["credits"] != 55.0 || credits(t, st, "alice") != 55.0 {
+		t.Errorf("Expected increment on top of concurrent write, got %v", credits(t, st, "alice"))
+	}
+}
+
+func TestModifyStopsWhenContextEnds(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.serveStore(fakeAccounts()...)
+	ctx, cancel := context.WithCancel(context.Background())
+	srv.handle("Update", func(msg map[string]interface{}) map[string]interface{} {
+		cancel()
+		return map[string]interface{}{"type": "Error", "code": CodeConflict, "message": "modified"}
+	})
+	client := srv.connect(nil)
+
+	attempts := 0
+	_, err := client.Modify(ctx, "accounts", "alice", func(doc *Document) error {
+		attempts++
+		return nil
+	})
+	if attempts != 1 || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation after one attempt, got %d (err=%v)", attempts, err)
+	}
+}
+
+func TestCollectionModify(t *testing.T) {
+	type account struct {
+		ID      string  `sqrl:"id"`
+		Credits float64 `sqrl:"credits"`
+	}
+	srv := newFakeServer(t)
+	st := srv.serveStore(fakeAccounts()...)
+	client := srv.connect(nil)
+
+	accounts := NewCollection[account](client, "accounts")
+	got, err := accounts.Modify(context.Background(), "bob", func(a *account) error {
+		a.Credits *= 2
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Modify failed: %v", err)
+	}
+	if got.ID != "bob" || got.Credits != 20 || credits(t, st, "bob") != 20.0 {
+		t.Errorf("Unexpected result %+v, stored %v", got, credits(t, st, "bob"))
+	}
+}
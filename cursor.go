@@ -0,0 +1,203 @@
+// SquirrelDB Go SDK - Cursors
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultCursorBatchSize is the number of documents a cursor fetches per request
+const DefaultCursorBatchSize = 100
+
+// Cursor iterates over the results of a query, fetching them in batches:
+//
+//	cur := client.Iterate(ctx, Table("users").Sort("email", SortAsc))
+//	defer cur.Close()
+//	for cur.Next() {
+//		var u User
+//		if err := cur.Decode(&u); err != nil {
+//			return err
+//		}
+//	}
+//	return cur.Err()
+//
+// Batches continue after the sort key of the last document seen rather than
+// using Skip, so each request stays cheap and documents inserted or deleted
+// during iteration do not shift results. The id is appended as a final sort
+// key so the order is total; every document must have the sort fields.
+type Cursor struct {
+	client    *Client
+	ctx       context.Context
+	query     *QueryBuilder
+	batchSize int
+	// remaining is the number of documents left under the query's limit, -1 if unlimited
+	remaining int
+
+	page    []Document
+	pos     int
+	current *Document
+	last    *Document
+	done    bool
+	err     error
+}
+
+// Iterate returns a cursor over the results of q. No request is made until
+// the first call to Next.
+func (c *Client) Iterate(ctx context.Context, q *QueryBuilder) *Cursor {
+	q = q.clone()
+	hasID := false
+	for _, s := range q.sorts {
+		hasID = hasID || s.Field == "id"
+	}
+	if !hasID {
+		q.sorts = append(q.sorts, SortSpec{Field: "id", Direction: SortAsc})
+	}
+	remaining := -1
+	if q.limitValue != nil {
+		remaining = *q.limitValue
+	}
+	return &Cursor{
+		client:    c,
+		ctx:       ctx,
+		query:     q,
+		batchSize: DefaultCursorBatchSize,
+		remaining: remaining,
+	}
+}
+
+// BatchSize sets the number of documents fetched per request
+func (cur *Cursor) BatchSize(n int) *Cursor {
+	if n > 0 {
+		cur.batchSize = n
+	}
+	return cur
+}
+
+// Next advances to the next document, fetching a batch if needed. It
+// returns false at the end of the results or on error, see Err.
+func (cur *Cursor) Next() bool {
+	if cur.err != nil {
+		return false
+	}
+	if cur.pos >= len(cur.page) {
+		if cur.done {
+			cur.current = nil
+			return false
+		}
+		if err := cur.fetch(); err != nil {
+			cur.err = err
+			cur.current = nil
+			return false
+		}
+		if len(cur.page) == 0 {
+			cur.current = nil
+			return false
+		}
+	}
+	cur.current = &cur.page[cur.pos]
+	cur.pos++
+	cur.last = cur.current
+	return true
+}
+
+func (cur *Cursor) fetch() error {
+	if err := cur.ctx.Err(); err != nil {
+		return err
+	}
+	limit := cur.batchSize
+	if cur.remaining >= 0 && cur.remaining < limit {
+		limit = cur.remaining
+	}
+	if limit == 0 {
+		cur.page, cur.pos, cur.done = nil, 0, true
+		return nil
+	}
+
+	q := cur.query.clone()
+	if cur.last != nil {
+		after, err := cur.after(cur.last)
+		if err != nil {
+			return err
+		}
+		// A single $and keeps the continuation from clashing with $and/$or keys in the filters
+		q.filters = []FilterCondition{And(append(q.filters, after)...)}
+		q.skipValue = nil
+	}
+	docs, err := cur.client.Find(cur.ctx, q.Limit(limit))
+	if err != nil {
+		return err
+	}
+
+	cur.page, cur.pos = docs, 0
+	cur.done = len(docs) < limit
+	if cur.remaining >= 0 {
+		cur.remaining -= len(docs)
+	}
+	return nil
+}
+
+// after builds the condition matching documents that sort after doc:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func (cur *Cursor) after(doc *Document) (FilterCondition, error) {
+	values := make([]interface{}, len(cur.query.sorts))
+	for i, s := range cur.query.sorts {
+		if s.Field == "id" {
+			values[i] = doc.Id
+			continue
+		}
+		v, ok := doc.Get(s.Field)
+		if !ok || v == nil {
+			return FilterCondition{}, fmt.Errorf("cursor: document %s has no sort field %s", doc.Id, s.Field)
+		}
+		values[i] = v
+	}
+
+	var terms []FilterCondition
+	for i, s := range cur.query.sorts {
+		var conds []FilterCondition
+		for j := 0; j < i; j++ {
+			conds = append(conds, Field(cur.query.sorts[j].Field).Eq(values[j]))
+		}
+		if s.Direction == SortDesc {
+			conds = append(conds, Field(s.Field).Lt(values[i]))
+		} else {
+			conds = append(conds, Field(s.Field).Gt(values[i]))
+		}
+		if len(conds) == 1 {
+			terms = append(terms, conds[0])
+		} else {
+			terms = append(terms, And(conds...))
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return Or(terms...), nil
+}
+
+// Document returns the current document
+func (cur *Cursor) Document() *Document {
+	return cur.current
+}
+
+// Decode the current document into v, see FromDocument
+func (cur *Cursor) Decode(v interface{}) error {
+	if cur.current == nil {
+		return errors.New("cursor: Decode called without a current document")
+	}
+	return cur.current.Decode(v)
+}
+
+// Err returns the error that stopped iteration, if any
+func (cur *Cursor) Err() error {
+	return cur.err
+}
+
+// Close stops the iteration and releases the buffered batch. Next returns
+// false after Close.
+func (cur *Cursor) Close() error {
+	cur.page, cur.pos, cur.current, cur.done = nil, 0, nil, true
+	return nil
+}
@@ -0,0 +1,110 @@
+// SquirrelDB Go SDK - Cursor Tests
+
+package squirreldb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func fakeScores(n int) []Document {
+	docs := make([]Document, n)
+	for i := range docs {
+		docs[i] = Document{
+			Id:         fmt.Sprintf("s%02d", i),
+			Collection: "scores",
+			// Repeated scores exercise the id tie-breaker
+			Data: map[string]interface{}{"score": float64(i % 4), "even": i%2 == 0},
+		}
+	}
+	return docs
+}
+
+func TestCursorWalksAllResultsInBatches(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.serveDocuments(fakeScores(10)...)
+	client := srv.connect(nil)
+
+	cur := client.Iterate(context.Background(), Table("scores").Sort("score", SortDesc)).BatchSize(3)
+	defer cur.Close()
+	var ids []string
+	lastScore := 99.0
+	for cur.Next() {
+		var v struct {
+			ID    string  `sqrl:"id"`
+			Score float64 `sqrl:"score"`
+		}
+		if err := cur.Decode(&v); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		if v.Score > lastScore {
+			t.Errorf("Expected descending scores, got %v after %v", v.Score, lastScore)
+		}
+		lastScore = v.Score
+		ids = append(ids, v.ID)
+	}
+	if err := cur.Err(); err != nil {
+		t.Fatalf("Cursor failed: %v", err)
+	}
+
+	if len(ids) != 10 {
+		t.Fatalf("Expected 10 documents, got %d: %v", len(ids), ids)
+	}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			t.Errorf("Document %s returned twice", id)
+		}
+		seen[id] = true
+	}
+	queries := srv.messages("Query")
+	if len(queries) != 4 {
+		t.Errorf("Expected 4 batch queries, got %d", len(queries))
+	}
+	for _, q := range queries {
+		if _, hasSkip := q["structured_query"].(map[string]interface{})["skip"]; hasSkip {
+			t.Error("Expected cursor not to page with skip")
+		}
+	}
+}
+
+func TestCursorKeepsFiltersAndLimit(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.serveDocuments(fakeScores(10)...)
+	client := srv.connect(nil)
+
+	cur := client.Iterate(context.Background(), Table("scores").Find(Field("even").Eq(true)).Limit(4)).BatchSize(3)
+	var ids []string
+	for cur.Next() {
+		ids = append(ids, cur.Document().Id)
+	}
+	if cur.Err() != nil {
+		t.Fatalf("Cursor failed: %v", cur.Err())
+	}
+	if fmt.Sprint(ids) != "[s00 s02 s04 s06]" {
+		t.Errorf("Unexpected documents: %v", ids)
+	}
+	if n := len(srv.messages("Query")); n != 2 {
+		t.Errorf("Expected 2 batch queries, got %d", n)
+	}
+}
+
+func TestCursorReportsErrors(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Query", func(msg map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "Error", "code": CodePermissionDenied, "message": "denied"}
+	})
+	client := srv.connect(nil)
+
+	cur := client.Iterate(context.Background(), Table("scores"))
+	if cur.Next() {
+		t.Fatal("Expected Next to fail")
+	}
+	if cur.Err() == nil {
+		t.Error("Expected cursor error")
+	}
+	if err := cur.Decode(&struct{}{}); err == nil {
+		t.Error("Expected Decode without a document to fail")
+	}
+}
@@ -0,0 +1,220 @@
+// SquirrelDB Go SDK - Change Diffs
+
+package squirreldb
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiffOp describes how a field changed
+type DiffOp string
+
+const (
+	DiffAdded    DiffOp = "added"
+	DiffRemoved  DiffOp = "removed"
+	DiffModified DiffOp = "modified"
+)
+
+// FieldChange is a single changed field. Before is nil for added fields
+// and After is nil for removed ones.
+type FieldChange struct {
+	// Path uses the dot syntax of Field() and Document.Get, e.g. "items.2.sku"
+	Path   string
+	Op     DiffOp
+	Before interface{}
+	After  interface{}
+
+	segments []string
+}
+
+// Diff is the list of field changes between two versions of a document, in
+// document order with object keys sorted
+type Diff []FieldChange
+
+// Added returns the fields present only in the new version
+func (d Diff) Added() []FieldChange {
+	return d.filter(DiffAdded)
+}
+
+// Removed returns the fields present only in the old version
+func (d Diff) Removed() []FieldChange {
+	return d.filter(DiffRemoved)
+}
+
+// Modified returns the fields whose value changed
+func (d Diff) Modified() []FieldChange {
+	return d.filter(DiffModified)
+}
+
+func (d Diff) filter(op DiffOp) []FieldChange {
+	var out []FieldChange
+	for _, c := range d {
+		if c.Op == op {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Diff compares the old and new document of the event. Insert events
+// yield only added fields and delete events only removed ones.
+func (e *ChangeEvent) Diff() (Diff, error) {
+	old, err := e.OldDocument()
+	if err != nil {
+		return nil, err
+	}
+	var before, after map[string]interface{}
+	if old != nil {
+		before = old.Data
+	}
+	if e.New != nil {
+		after = e.New.Data
+	} else if e.Document != nil && old == nil {
+		after = e.Document.Data
+	}
+	return DiffData(before, after), nil
+}
+
+// DiffData compares two document data maps, recursing into nested objects
+// and arrays. Arrays are compared index by index.
+func DiffData(before, after map[string]interface{}) Diff {
+	var d Diff
+	diffMaps(&d, nil, before, after)
+	return d
+}
+
+func diffMaps(d *Diff, path []string, before, after map[string]interface{}) {
+	keys := make([]string, 0, len(before)+len(after))
+	for k := range before {
+		keys = append(keys, k)
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		b, inBefore := before[k]
+		a, inAfter := after[k]
+		p := appendPath(path, k)
+		switch {
+		case !inBefore:
+			d.add(p, DiffAdded, nil, a)
+		case !inAfter:
+			d.add(p, DiffRemoved, b, nil)
+		default:
+			diffValues(d, p, b, a)
+		}
+	}
+}
+
+func diffValues(d *Diff, path []string, before, after interface{}) {
+	bm, bIsMap := before.(map[string]interface{})
+	am, aIsMap := after.(map[string]interface{})
+	if bIsMap && aIsMap {
+		diffMaps(d, path, bm, am)
+		return
+	}
+
+	bs, bIsSlice := before.([]interface{})
+	as, aIsSlice := after.([]interface{})
+	if bIsSlice && aIsSlice {
+		common := len(bs)
+		if len(as) < common {
+			common = len(as)
+		}
+		for i := 0; i < common; i++ {
+			diffValues(d, appendPath(path, strconv.Itoa(i)), bs[i], as[i])
+		}
+		for i := common; i < len(as); i++ {
+			d.add(appendPath(path, strconv.Itoa(i)), DiffAdded, nil, as[i])
+		}
+		// Remove trailing items from the end so indices stay valid when applied in order
+		for i := len(bs) - 1; i >= common; i-- {
+			d.add(appendPath(path, strconv.Itoa(i)), DiffRemoved, bs[i], nil)
+		}
+		return
+	}
+
+	if !valuesEqual(before, after) {
+		d.add(path, DiffModified, before, after)
+	}
+}
+
+func (d *Diff) add(path []string, op DiffOp, before, after interface{}) {
+	*d = append(*d, FieldChange{
+		Path:     strings.Join(path, "."),
+		Op:       op,
+		Before:   before,
+		After:    after,
+		segments: path,
+	})
+}
+
+func appendPath(path []string, key string) []string {
+	return append(append(make([]string, 0, len(path)+1), path...), key)
+}
+
+// valuesEqual compares leaf values, treating numbers of different Go types as equal
+func valuesEqual(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// PatchOperation is an RFC 6902 JSON Patch operation
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON omits the value of remove operations but keeps null values elsewhere
+func (p PatchOperation) MarshalJSON() ([]byte, error) {
+	if p.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{p.Op, p.Path})
+	}
+	type plain PatchOperation
+	return json.Marshal(plain(p))
+}
+
+// JSONPatch renders the diff as an RFC 6902 JSON Patch that transforms the
+// old document data into the new one. Paths are relative to Document.Data.
+func (d Diff) JSONPatch() []PatchOperation {
+	ops := make([]PatchOperation, 0, len(d))
+	for _, c := range d {
+		op := PatchOperation{Path: jsonPointer(c.segments), Value: c.After}
+		switch c.Op {
+		case DiffAdded:
+			op.Op = "add"
+		case DiffRemoved:
+			op.Op = "remove"
+			op.Value = nil
+		default:
+			op.Op = "replace"
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+func jsonPointer(segments []string) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteByte('/')
+		s = strings.ReplaceAll(s, "~", "~0")
+		b.WriteString(strings.ReplaceAll(s, "/", "~1"))
+	}
+	return b.String()
+}
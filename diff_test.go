@@ -0,0 +1,106 @@
+// SquirrelDB Go SDK - Change Diff Tests
+
+package squirreldb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffDataNested(t *testing.T) {
+	before := map[string]interface{}{
+		"name":    "Alice",
+		"age":     30.0,
+		"address": map[string]interface{}{"city": "Paris", "zip": "75001"},
+		"tags":    []interface{}{"a", "b", "c"},
+		"legacy":  true,
+	}
+	after := map[string]interface{}{
+		"name":    "Alice",
+		"age":     31,
+		"address": map[string]interface{}{"city": "Lyon", "country": "FR"},
+		"tags":    []interface{}{"a", "x"},
+		"email":   "a@example.com",
+	}
+
+	diff := DiffData(before, after)
+	got := make([]string, len(diff))
+	for i, c := range diff {
+		got[i] = string(c.Op) + " " + c.Path
+	}
+	want := []string{
+		"modified address.city",
+		"added address.country",
+		"removed address.zip",
+		"modified age",
+		"added email",
+		"removed legacy",
+		"modified tags.1",
+		"removed tags.2",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d changes, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Change %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+
+	if n := len(diff.Modified()); n != 3 {
+		t.Errorf("Expected 3 modified fields, got %d", n)
+	}
+	city := diff.Modified()[0]
+	if city.Before != "Paris" || city.After != "Lyon" {
+		t.Errorf("Unexpected before/after for address.city: %+v", city)
+	}
+}
+
+func TestDiffIgnoresNumericTypeDifferences(t *testing.T) {
+	diff := DiffData(map[string]interface{}{"n": 1.0}, map[string]interface{}{"n": int64(1)})
+	if len(diff) != 0 {
+		t.Errorf("Expected no changes, got %+v", diff)
+	}
+}
+
+func TestChangeEventDiff(t *testing.T) {
+	var event ChangeEvent
+	json.Unmarshal([]byte(`{
+		"type": "update",
+		"old": {"id": "u1", "data": {"name": "Alice"}},
+		"new": {"id": "u1", "data": {"name": "Alicia", "age": 30}}
+	}`), &event)
+
+	diff, err := event.Diff()
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(diff.Added()) != 1 || len(diff.Modified()) != 1 || len(diff.Removed()) != 0 {
+		t.Errorf("Unexpected diff: %+v", diff)
+	}
+
+	insert := ChangeEvent{Type: ChangeTypeInsert, New: &Document{Data: map[string]interface{}{"a": 1}}}
+	if diff, _ := insert.Diff(); len(diff.Added()) != 1 {
+		t.Errorf("Expected insert to add every field, got %+v", diff)
+	}
+}
+
+func TestDiffJSONPatch(t *testing.T) {
+	diff := DiffData(
+		map[string]interface{}{"a/b": 1, "items": []interface{}{1, 2, 3}, "gone": "x"},
+		map[string]interface{}{"a/b": 2, "items": []interface{}{1}, "new~key": nil},
+	)
+
+	data, err := json.Marshal(diff.JSONPatch())
+	if err != nil {
+		t.Fatalf("Failed to marshal patch: %v", err)
+	}
+	want := `[{"op":"replace","path":"/a~1b","value":2},` +
+		`{"op":"remove","path":"/gone"},` +
+		`{"op":"remove","path":"/items/2"},` +
+		`{"op":"remove","path":"/items/1"},` +
+		`{"op":"add","path":"/new~0key","value":null}]`
+	if string(data) != want {
+		t.Errorf("Unexpected patch:\n got %s\nwant %s", data, want)
+	}
+}
@@ -0,0 +1,288 @@
+// SquirrelDB Go SDK - Document Accessors
+
+package squirreldb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrFieldNotFound is returned by Document getters when a path does not exist
+var ErrFieldNotFound = errors.New("field not found")
+
+// FieldTypeError is returned by Document getters when a value has the wrong type
+type FieldTypeError struct {
+	Path string
+	Want string
+	Got  interface{}
+}
+
+func (e *FieldTypeError) Error() string {
+	return fmt.Sprintf("field %s: expected %s, got %T", e.Path, e.Want, e.Got)
+}
+
+// CreatedTime returns CreatedAt parsed as RFC 3339; zero if unset
+func (d *Document) CreatedTime() (time.Time, error) {
+	return parseTimestamp("created_at", d.CreatedAt)
+}
+
+// UpdatedTime returns UpdatedAt parsed as RFC 3339; zero if unset
+func (d *Document) UpdatedTime() (time.Time, error) {
+	return parseTimestamp("updated_at", d.UpdatedAt)
+}
+
+func parseTimestamp(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return t, nil
+}
+
+// OldDocument decodes Old into a Document. It returns nil if there is no
+// old value and an error if the old value is not a document.
+func (e *ChangeEvent) OldDocument() (*Document, error) {
+	if e.Old == nil || *e.Old == nil {
+		return nil, nil
+	}
+	m, ok := (*e.Old).(map[string]interface{})
+	if !ok {
+		return nil, &FieldTypeError{Path: "old", Want: "document", Got: *e.Old}
+	}
+	if _, hasID := m["id"]; !hasID {
+		return nil, &FieldTypeError{Path: "old", Want: "document", Got: *e.Old}
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var doc Document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("old: %w", err)
+	}
+	return &doc, nil
+}
+
+// Decode the document into the struct pointed to by v, see FromDocument
+func (d *Document) Decode(v interface{}) error {
+	return FromDocument(d, v)
+}
+
+// Get returns the value at path and whether it exists. Paths use the same
+// dot syntax as Field() in filters, e.g. "address.city", and numeric
+// segments index into arrays, e.g. "items.0.sku".
+func (d *Document) Get(path string) (interface{}, bool) {
+	var cur interface{} = d.Data
+	for _, key := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			v, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// Set the value at path, creating intermediate objects as needed
+func (d *Document) Set(path string, value interface{}) error {
+	if d.Data == nil {
+		d.Data = make(map[string]interface{})
+	}
+	keys := strings.Split(path, ".")
+	var cur interface{} = d.Data
+	for i, key := range keys {
+		last := i == len(keys)-1
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			if last {
+				node[key] = value
+				return nil
+			}
+			next, ok := node[key]
+			if !ok || next == nil {
+				next = make(map[string]interface{})
+				node[key] = next
+			}
+			cur = next
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return fmt.Errorf("field %s: index %q out of range", strings.Join(keys[:i+1], "."), key)
+			}
+			if last {
+				node[idx] = value
+				return nil
+			}
+			if node[idx] == nil {
+				node[idx] = make(map[string]interface{})
+			}
+			cur = node[idx]
+		default:
+			return &FieldTypeError{Path: strings.Join(keys[:i], "."), Want: "object or array", Got: cur}
+		}
+	}
+	return nil
+}
+
+func (d *Document) lookup(path string) (interface{}, error) {
+	v, ok := d.Get(path)
+	if !ok {
+		return nil, fmt.Errorf("field %s: %w", path, ErrFieldNotFound)
+	}
+	return v, nil
+}
+
+// GetString returns the string at path. It is not named String so that
+// Document does not appear to implement fmt.Stringer.
+func (d *Document) GetString(path string) (string, error) {
+	v, err := d.lookup(path)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", &FieldTypeError{Path: path, Want: "string", Got: v}
+	}
+	return s, nil
+}
+
+// StringOr returns the string at path, or def if it is missing or not a string
+func (d *Document) StringOr(path, def string) string {
+	if s, err := d.GetString(path); err == nil {
+		return s
+	}
+	return def
+}
+
+// Int64 returns the integer at path; fractional numbers are rejected
+func (d *Document) Int64(path string) (int64, error) {
+	v, err := d.lookup(path)
+	if err != nil {
+		return 0, err
+	}
+	if n, isInt := v.(int64); isInt {
+		return n, nil
+	}
+	f, ok := toFloat(v)
+	n, whole := floatInt64(f)
+	if !ok || !whole {
+		return 0, &FieldTypeError{Path: path, Want: "integer", Got: v}
+	}
+	return n, nil
+}
+
+// Int64Or returns the integer at path, or def if it is missing or not an integer
+func (d *Document) Int64Or(path string, def int64) int64 {
+	if n, err := d.Int64(path); err == nil {
+		return n
+	}
+	return def
+}
+
+// Float returns the number at path
+func (d *Document) Float(path string) (float64, error) {
+	v, err := d.lookup(path)
+	if err != nil {
+		return 0, err
+	}
+	f, ok := toFloat(v)
+	if !ok {
+		return 0, &FieldTypeError{Path: path, Want: "number", Got: v}
+	}
+	return f, nil
+}
+
+// FloatOr returns the number at path, or def if it is missing or not a number
+func (d *Document) FloatOr(path string, def float64) float64 {
+	if f, err := d.Float(path); err == nil {
+		return f
+	}
+	return def
+}
+
+// Bool returns the boolean at path
+func (d *Document) Bool(path string) (bool, error) {
+	v, err := d.lookup(path)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, &FieldTypeError{Path: path, Want: "bool", Got: v}
+	}
+	return b, nil
+}
+
+// BoolOr returns the boolean at path, or def if it is missing or not a boolean
+func (d *Document) BoolOr(path string, def bool) bool {
+	if b, err := d.Bool(path); err == nil {
+		return b
+	}
+	return def
+}
+
+// Time returns the RFC 3339 timestamp at path
+func (d *Document) Time(path string) (time.Time, error) {
+	v, err := d.lookup(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("field %s: %w", path, err)
+		}
+		return parsed, nil
+	}
+	return time.Time{}, &FieldTypeError{Path: path, Want: "timestamp", Got: v}
+}
+
+// TimeOr returns the timestamp at path, or def if it is missing or invalid
+func (d *Document) TimeOr(path string, def time.Time) time.Time {
+	if t, err := d.Time(path); err == nil {
+		return t
+	}
+	return def
+}
+
+// Slice returns the array at path
+func (d *Document) Slice(path string) ([]interface{}, error) {
+	v, err := d.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.([]interface{})
+	if !ok {
+		return nil, &FieldTypeError{Path: path, Want: "array", Got: v}
+	}
+	return s, nil
+}
+
+// SliceOr returns the array at path, or def if it is missing or not an array
+func (d *Document) SliceOr(path string, def []interface{}) []interface{} {
+	if s, err := d.Slice(path); err == nil {
+		return s
+	}
+	return def
+}
@@ -0,0 +1,191 @@
+// SquirrelDB Go SDK - Document Accessor Tests
+
+package squirreldb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func testDocument(t *testing.T) *Document {
+	t.Helper()
+	var doc Document
+	err := json.Unmarshal([]byte(`{
+		"id": "u1",
+		"data": {
+			"name": "Alice",
+			"age": 30,
+			"rating": 4.5,
+			"active": true,
+			"joined": "2024-01-02T03:04:05Z",
+			"address": {"city": "Paris", "geo": {"lat": 48.8}},
+			"items": [{"sku": "A1"}, {"sku": "B2"}]
+		}
+	}`), &doc)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal document: %v", err)
+	}
+	return &doc
+}
+
+func TestDocumentGetPaths(t *testing.T) {
+	doc := testDocument(t)
+
+	if v, ok := doc.Get("address.city"); !ok || v != "Paris" {
+		t.Errorf("Expected address.city 'Paris', got %v", v)
+	}
+	if v, ok := doc.Get("items.1.sku"); !ok || v != "B2" {
+		t.Errorf("Expected items.1.sku 'B2', got %v", v)
+	}
+	if _, ok := doc.Get("items.5.sku"); ok {
+		t.Error("Expected out-of-range index to be missing")
+	}
+	if _, ok := doc.Get("name.first"); ok {
+		t.Error("Expected path through a string to be missing")
+	}
+}
+
+func TestDocumentTypedGetters(t *testing.T) {
+	doc := testDocument(t)
+
+	if s, err := doc.GetString("name"); err != nil || s != "Alice" {
+		t.Errorf("String: got %q, %v", s, err)
+	}
+	if n, err := doc.Int64("age"); err != nil || n != 30 {
+		t.Errorf("Int64: got %d, %v", n, err)
+	}
+	if f, err := doc.Float("address.geo.lat"); err != nil || f != 48.8 {
+		t.Errorf("Float: got %v, %v", f, err)
+	}
+	if b, err := doc.Bool("active"); err != nil || !b {
+		t.Errorf("Bool: got %v, %v", b, err)
+	}
+	if tm, err := doc.Time("joined"); err != nil || !tm.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Time: got %v, %v", tm, err)
+	}
+	if s, err := doc.Slice("items"); err != nil || len(s) != 2 {
+		t.Errorf("Slice: got %v, %v", s, err)
+	}
+}
+
+func TestDocumentGetterErrors(t *testing.T) {
+	doc := testDocument(t)
+
+	if _, err := doc.GetString("missing"); !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("Expected ErrFieldNotFound, got %v", err)
+	}
+	var typeErr *FieldTypeError
+	if _, err := doc.Int64("rating"); !errors.As(err, &typeErr) {
+		t.Errorf("Expected FieldTypeError for fractional int, got %v", err)
+	} else if typeErr.Path != "rating" || typeErr.Want != "integer" {
+		t.Errorf("Unexpected type error: %+v", typeErr)
+	}
+	overflow := &Document{Data: map[string]interface{}{"big": 0x1p63, "max": int64(math.MaxInt64), "min": -0x1p63}}
+	if n, err := overflow.Int64("big"); err == nil {
+		t.Errorf("Expected 2^63 to overflow int64, got %d", n)
+	}
+	if n, err := overflow.Int64("max"); err != nil || n != math.MaxInt64 {
+		t.Errorf("Expected MaxInt64, got %d, %v", n, err)
+	}
+	if n, err := overflow.Int64("min"); err != nil || n != math.MinInt64 {
+		t.Errorf("Expected MinInt64, got %d, %v", n, err)
+	}
+	if doc.StringOr("age", "n/a") != "n/a" {
+		t.Error("Expected StringOr to fall back on type mismatch")
+	}
+	if doc.Int64Or("missing", 7) != 7 {
+		t.Error("Expected Int64Or to fall back on missing field")
+	}
+	if !doc.BoolOr("missing", true) {
+		t.Error("Expected BoolOr to fall back on missing field")
+	}
+}
+
+func TestDocumentSet(t *testing.T) {
+	doc := &Document{}
+	if err := doc.Set("address.city", "Lyon"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if doc.StringOr("address.city", "") != "Lyon" {
+		t.Errorf("Expected address.city 'Lyon', got %v", doc.Data)
+	}
+
+	doc.Set("items", []interface{}{map[string]interface{}{"sku": "A1"}})
+	if err := doc.Set("items.0.qty", 2); err != nil {
+		t.Fatalf("Set into array failed: %v", err)
+	}
+	if doc.Int64Or("items.0.qty", 0) != 2 {
+		t.Error("Expected items.0.qty to be 2")
+	}
+
+	if err := doc.Set("address.city.name", "x"); err == nil {
+		t.Error("Expected error setting through a string")
+	}
+	if err := doc.Set("items.3.qty", 1); err == nil {
+		t.Error("Expected error for out-of-range index")
+	}
+}
+
+func TestDocumentDecode(t *testing.T) {
+	var v struct {
+		ID   string `sqrl:"id"`
+		Name string `sqrl:"name"`
+		Age  int    `sqrl:"age"`
+	}
+	if err := testDocument(t).Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if v.ID != "u1" || v.Name != "Alice" || v.Age != 30 {
+		t.Errorf("Unexpected decoded value: %+v", v)
+	}
+}
+
+func TestDocumentParsedTimestamps(t *testing.T) {
+	doc := &Document{CreatedAt: "2024-01-02T03:04:05Z"}
+	created, err := doc.CreatedTime()
+	if err != nil || !created.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("CreatedTime: got %v, %v", created, err)
+	}
+	if updated, err := doc.UpdatedTime(); err != nil || !updated.IsZero() {
+		t.Errorf("Expected zero UpdatedTime when unset, got %v, %v", updated, err)
+	}
+	doc.UpdatedAt = "not a time"
+	if _, err := doc.UpdatedTime(); err == nil {
+		t.Error("Expected error for invalid updated_at")
+	}
+}
+
+func TestChangeEventOldDocument(t *testing.T) {
+	var event ChangeEvent
+	json.Unmarshal([]byte(`{"type": "update", "old": {"id": "u1", "data": {"name": "Alice"}}}`), &event)
+	old, err := event.OldDocument()
+	if err != nil {
+		t.Fatalf("OldDocument failed: %v", err)
+	}
+	if old.Id != "u1" || old.Data["name"] != "Alice" {
+		t.Errorf("Unexpected old document: %+v", old)
+	}
+
+	var scalar interface{} = 42.0
+	event.Old = &scalar
+	var typeErr *FieldTypeError
+	if _, err := event.OldDocument(); !errors.As(err, &typeErr) {
+		t.Errorf("Expected FieldTypeError for non-document old, got %v", err)
+	}
+
+	event.Old = nil
+	if old, err := event.OldDocument(); old != nil || err != nil {
+		t.Errorf("Expected nil old document, got %v, %v", old, err)
+	}
+}
+
+func TestDocumentIsNotStringer(t *testing.T) {
+	var v interface{} = &Document{}
+	if _, ok := v.(fmt.Stringer); ok {
+		t.Error("Expected Document not to implement fmt.Stringer")
+	}
+}
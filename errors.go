@@ -0,0 +1,109 @@
+// SquirrelDB Go SDK - Errors
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error categories shared by database, cache and storage errors, for use with errors.Is
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrConflict         = errors.New("conflict")
+	ErrValidation       = errors.New("validation failed")
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// Server error codes
+const (
+	CodeNotFound         = "not_found"
+	CodeConflict         = "conflict"
+	CodeValidation       = "validation_error"
+	CodePermissionDenied = "permission_denied"
+	CodeUnavailable      = "unavailable"
+	CodeInternal         = "internal"
+	CodeCancelled        = "cancelled"
+	// CodeSerializationFailure aborts a transaction that conflicted with a
+	// concurrent write; it may succeed if run again
+	CodeSerializationFailure = "serialization_failure"
+)
+
+var serverCodeSentinels = map[string]error{
+	CodeNotFound:             ErrNotFound,
+	CodeConflict:             ErrConflict,
+	CodeValidation:           ErrValidation,
+	CodePermissionDenied:     ErrPermissionDenied,
+	CodeCancelled:            context.Canceled,
+	CodeSerializationFailure: ErrConflict,
+}
+
+// ServerError is an Error message returned by the SquirrelDB server
+type ServerError struct {
+	Code      string
+	Message   string
+	RequestID string
+	Details   map[string]interface{}
+	// retryable is set by the server when the request may succeed if retried
+	retryable bool
+}
+
+func (e *ServerError) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Retryable reports whether the server marked the request as one that may
+// succeed if retried
+func (e *ServerError) Retryable() bool {
+	return e.retryable
+}
+
+// Is matches the error category sentinels (ErrNotFound, ErrConflict, ...)
+func (e *ServerError) Is(target error) bool {
+	sentinel, ok := serverCodeSentinels[strings.ToLower(e.Code)]
+	return ok && sentinel == target
+}
+
+// NotFoundError is returned when a document looked up by id does not exist
+type NotFoundError struct {
+	Collection string
+	ID         string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("document %s/%s not found", e.Collection, e.ID)
+}
+
+// Is matches ErrNotFound
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// parseServerError builds a ServerError from a raw Error message
+func parseServerError(message []byte) *ServerError {
+	var wire struct {
+		ID        string                 `json:"id"`
+		Code      string                 `json:"code"`
+		Message   string                 `json:"message"`
+		RequestID string                 `json:"request_id"`
+		Retryable bool                   `json:"retryable"`
+		Details   map[string]interface{} `json:"details"`
+	}
+	json.Unmarshal(message, &wire)
+	if wire.RequestID == "" {
+		wire.RequestID = wire.ID
+	}
+	return &ServerError{
+		Code:      wire.Code,
+		Message:   wire.Message,
+		RequestID: wire.RequestID,
+		Details:   wire.Details,
+		retryable: wire.Retryable,
+	}
+}
@@ -0,0 +1,109 @@
+// SquirrelDB Go SDK - Error Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseServerError(t *testing.T) {
+	err := parseServerError([]byte(`{
+		"type": "Error",
+		"id": "req-7",
+		"code": "not_found",
+		"message": "document missing",
+		"retryable": false,
+		"details": {"collection": "users"}
+	}`))
+
+	if err.Code != CodeNotFound {
+		t.Errorf("Expected code 'not_found', got '%s'", err.Code)
+	}
+	if err.RequestID != "req-7" {
+		t.Errorf("Expected request id to fall back to 'req-7', got '%s'", err.RequestID)
+	}
+	if err.Details["collection"] != "users" {
+		t.Errorf("Expected details.collection 'users', got '%v'", err.Details["collection"])
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Expected errors.Is(err, ErrNotFound)")
+	}
+	if errors.Is(err, ErrConflict) {
+		t.Error("Expected errors.Is(err, ErrConflict) to be false")
+	}
+}
+
+func TestServerErrorReturnedFromClient(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Update", func(msg map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"type":       "Error",
+			"code":       "conflict",
+			"message":    "document changed",
+			"request_id": "srv-42",
+			"retryable":  true,
+		}
+	})
+	client := srv.connect(nil)
+
+	_, err := client.Update(context.Background(), "users", "doc-1", map[string]interface{}{})
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) {
+		t.Fatalf("Expected *ServerError, got %T (%v)", err, err)
+	}
+	if serverErr.RequestID != "srv-42" {
+		t.Errorf("Expected request id 'srv-42', got '%s'", serverErr.RequestID)
+	}
+	if !errors.Is(err, ErrConflict) {
+		t.Error("Expected errors.Is(err, ErrConflict)")
+	}
+	if !IsRetryable(err) || !serverErr.Retryable() {
+		t.Error("Expected server-flagged error to be retryable")
+	}
+}
+
+func TestParseStorageError(t *testing.T) {
+	body := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message><Resource>/bucket/key</Resource><RequestId>abc123</RequestId></Error>`)
+	err := parseStorageError(404, body)
+
+	if err.Code != "NoSuchKey" {
+		t.Errorf("Expected code 'NoSuchKey', got '%s'", err.Code)
+	}
+	if err.RequestID != "abc123" {
+		t.Errorf("Expected request id 'abc123', got '%s'", err.RequestID)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Expected errors.Is(err, ErrNotFound)")
+	}
+	if err.Retryable() {
+		t.Error("Expected NoSuchKey to be non-retryable")
+	}
+
+	plain := parseStorageError(503, []byte("service down"))
+	if plain.Message != "service down" || !plain.Retryable() {
+		t.Errorf("Expected retryable plain-text error, got %+v", plain)
+	}
+}
+
+func TestParseCacheError(t *testing.T) {
+	err := parseCacheError("WRONGTYPE Operation against a key holding the wrong kind of value")
+	if err.Prefix != "WRONGTYPE" {
+		t.Errorf("Expected prefix 'WRONGTYPE', got '%s'", err.Prefix)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Error("Expected errors.Is(err, ErrValidation)")
+	}
+
+	if !parseCacheError("LOADING Redis is loading the dataset in memory").Retryable() {
+		t.Error("Expected LOADING to be retryable")
+	}
+	if !errors.Is(parseCacheError("NOPERM this user has no permissions"), ErrPermissionDenied) {
+		t.Error("Expected NOPERM to match ErrPermissionDenied")
+	}
+	if e := parseCacheError("unknown failure"); e.Prefix != "ERR" || e.Message != "unknown failure" {
+		t.Errorf("Expected generic ERR, got %+v", e)
+	}
+}
@@ -0,0 +1,103 @@
+// SquirrelDB Go SDK - Typed Fields
+
+package squirreldb
+
+// TypedField is a field expression whose comparisons only accept values of
+// type T, so filters on generated collection fields are checked at compile
+// time. See cmd/sqrlgen.
+type TypedField[T any] struct {
+	name string
+}
+
+// NewField creates a typed field expression
+func NewField[T any](name string) TypedField[T] {
+	return TypedField[T]{name: name}
+}
+
+// Name returns the field name, e.g. for QueryBuilder.Sort
+func (f TypedField[T]) Name() string {
+	return f.name
+}
+
+// Expr returns the untyped field expression
+func (f TypedField[T]) Expr() *FieldExpr {
+	return Field(f.name)
+}
+
+// Eq creates an equal condition
+func (f TypedField[T]) Eq(value T) FilterCondition {
+	return f.Expr().Eq(value)
+}
+
+// Ne creates a not equal condition
+func (f TypedField[T]) Ne(value T) FilterCondition {
+	return f.Expr().Ne(value)
+}
+
+// Gt creates a greater than condition
+func (f TypedField[T]) Gt(value T) FilterCondition {
+	return f.Expr().Gt(value)
+}
+
+// Gte creates a greater than or equal condition
+func (f TypedField[T]) Gte(value T) FilterCondition {
+	return f.Expr().Gte(value)
+}
+
+// Lt creates a less than condition
+func (f TypedField[T]) Lt(value T) FilterCondition {
+	return f.Expr().Lt(value)
+}
+
+// Lte creates a less than or equal condition
+func (f TypedField[T]) Lte(value T) FilterCondition {
+	return f.Expr().Lte(value)
+}
+
+// In creates a value in array condition
+func (f TypedField[T]) In(values ...T) FilterCondition {
+	return f.Expr().In(toInterfaces(values)...)
+}
+
+// NotIn creates a value not in array condition
+func (f TypedField[T]) NotIn(values ...T) FilterCondition {
+	return f.Expr().NotIn(toInterfaces(values)...)
+}
+
+// Exists creates a field exists condition
+func (f TypedField[T]) Exists(value bool) FilterCondition {
+	return f.Expr().Exists(value)
+}
+
+// StringField is a typed string field with the string conditions
+type StringField struct {
+	TypedField[string]
+}
+
+// NewStringField creates a typed string field expression
+func NewStringField(name string) StringField {
+	return StringField{NewField[string](name)}
+}
+
+// Contains creates a string contains condition
+func (f StringField) Contains(value string) FilterCondition {
+	return f.Expr().Contains(value)
+}
+
+// StartsWith creates a string starts with condition
+func (f StringField) StartsWith(value string) FilterCondition {
+	return f.Expr().StartsWith(value)
+}
+
+// EndsWith creates a string ends with condition
+func (f StringField) EndsWith(value string) FilterCondition {
+	return f.Expr().EndsWith(value)
+}
+
+func toInterfaces[T any](values []T) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
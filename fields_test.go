@@ -0,0 +1,37 @@
+// SquirrelDB Go SDK - Typed Fields Tests
+
+package squirreldb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypedFieldConditions(t *testing.T) {
+	age := NewField[int64]("age")
+	if age.Name() != "age" {
+		t.Errorf("Expected name 'age', got '%s'", age.Name())
+	}
+	if cond := age.Gte(18); cond != Field("age").Gte(int64(18)) {
+		t.Errorf("Expected typed condition to match untyped one, got %+v", cond)
+	}
+	cond := age.In(1, 2, 3)
+	if cond.Operator != "$in" || !reflect.DeepEqual(cond.Value, []interface{}{int64(1), int64(2), int64(3)}) {
+		t.Errorf("Unexpected in condition: %+v", cond)
+	}
+}
+
+func TestStringFieldConditions(t *testing.T) {
+	email := NewStringField("address.email")
+	if cond := email.EndsWith("@example.com"); cond.Field != "address.email" || cond.Operator != "$endsWith" {
+		t.Errorf("Unexpected endsWith condition: %+v", cond)
+	}
+	if cond := email.Eq("ann@example.com"); cond.Operator != "$eq" || cond.Value != "ann@example.com" {
+		t.Errorf("Unexpected eq condition: %+v", cond)
+	}
+
+	q := Table("users").Find(email.StartsWith("ann"), NewField[bool]("active").Eq(true)).CompileStructured()
+	if q.Filter["address.email"]["$startsWith"] != "ann" || q.Filter["active"]["$eq"] != true {
+		t.Errorf("Unexpected compiled filter: %v", q.Filter)
+	}
+}
@@ -0,0 +1,257 @@
+// SquirrelDB Go SDK - Indexes
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// IndexSpec describes a secondary index. A single key makes a single-field
+// index and several keys a compound index, in key order.
+type IndexSpec struct {
+	Collection string
+	// Name defaults to the keys joined with their directions, e.g. "email_asc"
+	Name string
+	Keys []SortSpec
+	// Unique rejects writes that would give two documents the same key
+	Unique bool
+	// TTL expires documents this long after the time in the indexed field;
+	// only valid on a single-field index and counted in whole seconds
+	TTL time.Duration
+}
+
+// IndexName returns Name, or the default name derived from the keys
+func (s IndexSpec) IndexName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	parts := make([]string, len(s.Keys))
+	for i, k := range s.Keys {
+		dir := k.Direction
+		if dir == "" {
+			dir = SortAsc
+		}
+		parts[i] = k.Field + "_" + string(dir)
+	}
+	return strings.Join(parts, "_")
+}
+
+func (s IndexSpec) validate() error {
+	if s.Collection == "" {
+		return errors.New("index: no collection")
+	}
+	if len(s.Keys) == 0 {
+		return fmt.Errorf("index on %s: no keys", s.Collection)
+	}
+	if s.TTL > 0 && len(s.Keys) > 1 {
+		return fmt.Errorf("index %s: TTL requires a single-field index", s.IndexName())
+	}
+	if s.TTL < 0 || (s.TTL > 0 && s.TTL < time.Second) {
+		return fmt.Errorf("index %s: TTL %v must be 0 or at least one second", s.IndexName(), s.TTL)
+	}
+	return nil
+}
+
+// sameDefinition reports whether two specs index the same way, ignoring names
+func (s IndexSpec) sameDefinition(o IndexSpec) bool {
+	if len(s.Keys) != len(o.Keys) || s.Unique != o.Unique || s.TTL/time.Second != o.TTL/time.Second {
+		return false
+	}
+	for i := range s.Keys {
+		a, b := s.Keys[i], o.Keys[i]
+		if a.Direction == "" {
+			a.Direction = SortAsc
+		}
+		if b.Direction == "" {
+			b.Direction = SortAsc
+		}
+		if a != b {
+			return false
+		}
+	}
+	return true
+}
+
+type wireIndex struct {
+	Name       string     `json:"name"`
+	Keys       []SortSpec `json:"keys"`
+	Unique     bool       `json:"unique,omitempty"`
+	TTLSeconds int64      `json:"ttl_seconds,omitempty"`
+}
+
+func (s IndexSpec) wire() wireIndex {
+	keys := make([]SortSpec, len(s.Keys))
+	for i, k := range s.Keys {
+		if k.Direction == "" {
+			k.Direction = SortAsc
+		}
+		keys[i] = k
+	}
+	return wireIndex{Name: s.IndexName(), Keys: keys, Unique: s.Unique, TTLSeconds: int64(s.TTL / time.Second)}
+}
+
+// CreateIndex builds an index; it fails with an error matching ErrConflict
+// if an index with the same name exists, and with one matching
+// ErrValidation if a unique index is violated by existing documents
+func (c *Client) CreateIndex(ctx context.Context, spec IndexSpec) error {
+	if err := spec.validate(); err != nil {
+		return err
+	}
+	_, err := c.admin(ctx, map[string]interface{}{
+		"type":       "CreateIndex",
+		"collection": spec.Collection,
+		"index":      spec.wire(),
+	})
+	return err
+}
+
+// DropIndex removes an index by name
+func (c *Client) DropIndex(ctx context.Context, collection, name string) error {
+	_, err := c.admin(ctx, map[string]interface{}{
+		"type":       "DropIndex",
+		"collection": collection,
+		"name":       name,
+	})
+	return err
+}
+
+// ListIndexes returns the secondary indexes of a collection
+func (c *Client) ListIndexes(ctx context.Context, collection string) ([]IndexSpec, error) {
+	result, err := c.request(ctx, map[string]interface{}{
+		"type":       "ListIndexes",
+		"collection": collection,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Indexes []wireIndex `json:"indexes"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, err
+	}
+	specs := make([]IndexSpec, len(resp.Indexes))
+	for i, w := range resp.Indexes {
+		specs[i] = IndexSpec{
+			Collection: collection,
+			Name:       w.Name,
+			Keys:       w.Keys,
+			Unique:     w.Unique,
+			TTL:        time.Duration(w.TTLSeconds) * time.Second,
+		}
+	}
+	return specs, nil
+}
+
+// IndexChanges reports what EnsureIndexes did
+type IndexChanges struct {
+	Created   []IndexSpec
+	Dropped   []IndexSpec
+	Unchanged []IndexSpec
+	// Unlisted are existing indexes that are not in the desired specs; they
+	// are left in place
+	Unlisted []IndexSpec
+}
+
+// EnsureIndexes makes the indexes of the collections in specs match specs:
+// missing indexes are created, and indexes whose name is reused with a
+// different definition are dropped and recreated. An existing index with
+// the same definition under another name counts as present unless another
+// spec names it. Indexes that are not listed are reported but never
+// dropped. Two specs with the same name on one collection are an error.
+func (c *Client) EnsureIndexes(ctx context.Context, specs []IndexSpec) (*IndexChanges, error) {
+	changes := &IndexChanges{}
+	var collections []string
+	desired := make(map[string][]IndexSpec)
+	for _, spec := range specs {
+		if err := spec.validate(); err != nil {
+			return changes, err
+		}
+		if _, ok := desired[spec.Collection]; !ok {
+			collections = append(collections, spec.Collection)
+		}
+		desired[spec.Collection] = append(desired[spec.Collection], spec)
+	}
+
+	for _, collection := range collections {
+		existing, err := c.ListIndexes(ctx, collection)
+		if err != nil {
+			return changes, err
+		}
+		specs := desired[collection]
+		matched := make([]bool, len(existing))
+		// matches[j] is the existing index claimed by specs[j], or -1
+		matches := make([]int, len(specs))
+		names := make(map[string]bool, len(specs))
+		for j, spec := range specs {
+			name := spec.IndexName()
+			if names[name] {
+				return changes, fmt.Errorf("index %s on %s: listed more than once", name, collection)
+			}
+			names[name] = true
+			matches[j] = -1
+			for i, ex := range existing {
+				if ex.Name == name {
+					matched[i] = true
+					matches[j] = i
+					break
+				}
+			}
+		}
+		// Only then fall back to indexes with the same definition, so a
+		// fallback never claims an index another spec names exactly
+		for j, spec := range specs {
+			if matches[j] >= 0 {
+				continue
+			}
+			for i, ex := range existing {
+				if !matched[i] && ex.sameDefinition(spec) {
+					matched[i] = true
+					matches[j] = i
+					break
+				}
+			}
+		}
+
+		for j, spec := range specs {
+			name := spec.IndexName()
+			action := "create"
+			var old IndexSpec
+			if i := matches[j]; i >= 0 {
+				old = existing[i]
+				if old.sameDefinition(spec) {
+					action = "keep"
+				} else {
+					action = "replace"
+				}
+			}
+
+			switch action {
+			case "keep":
+				changes.Unchanged = append(changes.Unchanged, spec)
+				continue
+			case "replace":
+				if err := c.DropIndex(ctx, collection, name); err != nil {
+					return changes, err
+				}
+				changes.Dropped = append(changes.Dropped, old)
+			}
+			if err := c.CreateIndex(ctx, spec); err != nil {
+				return changes, err
+			}
+			changes.Created = append(changes.Created, spec)
+		}
+
+		for i, ex := range existing {
+			if !matched[i] {
+				changes.Unlisted = append(changes.Unlisted, ex)
+			}
+		}
+	}
+	return changes, nil
+}
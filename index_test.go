@@ -0,0 +1,179 @@
+// SquirrelDB Go SDK - Index Tests
+
+package squirreldb
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// serveIndexes answers index management requests from an in-memory list
+func serveIndexes(srv *fakeServer, existing ...map[string]interface{}) {
+	var mu sync.Mutex
+	indexes := existing
+	srv.handle("ListIndexes", func(msg map[string]interface{}) map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return map[string]interface{}{"type": "Result", "indexes": indexes}
+	})
+	srv.handle("CreateIndex", func(msg map[string]interface{}) map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		index := msg["index"].(map[string]interface{})
+		for _, ix := range indexes {
+			if ix["name"] == index["name"] {
+				return map[string]interface{}{"type": "Error", "code": CodeConflict, "message": "index exists"}
+			}
+		}
+		indexes = append(indexes, index)
+		return map[string]interface{}{"type": "Result"}
+	})
+	srv.handle("DropIndex", func(msg map[string]interface{}) map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		for i, ix := range indexes {
+			if ix["name"] == msg["name"] {
+				indexes = append(indexes[:i], indexes[i+1:]...)
+				return map[string]interface{}{"type": "Result"}
+			}
+		}
+		return map[string]interface{}{"type": "Error", "code": CodeNotFound, "message": "no such index"}
+	})
+}
+
+func wireIndexKeys(fields ...string) []interface{} {
+	keys := make([]interface{}, len(fields))
+	for i, f := range fields {
+		keys[i] = map[string]interface{}{"field": f, "direction": "asc"}
+	}
+	return keys
+}
+
+func TestIndexSpecName(t *testing.T) {
+	spec := IndexSpec{Collection: "users", Keys: []SortSpec{{Field: "org"}, {Field: "created_at", Direction: SortDesc}}}
+	if name := spec.IndexName(); name != "org_asc_created_at_desc" {
+		t.Errorf("Unexpected default name %q", name)
+	}
+	spec.TTL = time.Hour
+	if err := spec.validate(); err == nil {
+		t.Error("Expected TTL on compound index to be rejected")
+	}
+	spec.Keys = spec.Keys[:1]
+	spec.TTL = 500 * time.Millisecond
+	if err := spec.validate(); err == nil {
+		t.Error("Expected sub-second TTL to be rejected")
+	}
+}
+
+func TestCreateAndListIndexes(t *testing.T) {
+	srv := newFakeServer(t)
+	serveIndexes(srv)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	err := client.CreateIndex(ctx, IndexSpec{
+		Collection: "sessions",
+		Keys:       []SortSpec{{Field: "expires_at"}},
+		TTL:        30 * time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("CreateIndex failed: %v", err)
+	}
+	indexes, err := client.ListIndexes(ctx, "sessions")
+	if err != nil {
+		t.Fatalf("ListIndexes failed: %v", err)
+	}
+	if len(indexes) != 1 || indexes[0].Name != "expires_at_asc" || indexes[0].TTL != 30*time.Minute {
+		t.Errorf("Unexpected indexes: %+v", indexes)
+	}
+	if err := client.DropIndex(ctx, "sessions", "expires_at_asc"); err != nil {
+		t.Fatalf("DropIndex failed: %v", err)
+	}
+}
+
+func TestEnsureIndexes(t *testing.T) {
+	srv := newFakeServer(t)
+	serveIndexes(srv,
+		// Same definition as desired, under another name
+		map[string]interface{}{"name": "by_email", "keys": wireIndexKeys("email"), "unique": true},
+		// Desired name reused with a different definition
+		map[string]interface{}{"name": "org_lookup", "keys": wireIndexKeys("org")},
+		// Not desired
+		map[string]interface{}{"name": "legacy", "keys": wireIndexKeys("old_field")},
+	)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	desired := []IndexSpec{
+		{Collection: "users", Keys: []SortSpec{{Field: "email", Direction: SortAsc}}, Unique: true},
+		{Collection: "users", Name: "org_lookup", Keys: []SortSpec{{Field: "org"}, {Field: "name"}}},
+		{Collection: "users", Keys: []SortSpec{{Field: "created_at", Direction: SortDesc}}},
+	}
+	changes, err := client.EnsureIndexes(ctx, desired)
+	if err != nil {
+		t.Fatalf("EnsureIndexes failed: %v", err)
+	}
+	if len(changes.Unchanged) != 1 || changes.Unchanged[0].Keys[0].Field != "email" {
+		t.Errorf("Expected email index to be kept, got %+v", changes.Unchanged)
+	}
+	if len(changes.Dropped) != 1 || len(changes.Dropped[0].Keys) != 1 {
+		t.Errorf("Expected old org_lookup to be dropped, got %+v", changes.Dropped)
+	}
+	if len(changes.Created) != 2 {
+		t.Errorf("Expected 2 indexes to be created, got %+v", changes.Created)
+	}
+	if len(changes.Unlisted) != 1 || changes.Unlisted[0].Name != "legacy" {
+		t.Errorf("Expected legacy index to be reported, got %+v", changes.Unlisted)
+	}
+	if len(srv.messages("DropIndex")) != 1 {
+		t.Error("Expected only the redefined index to be dropped")
+	}
+
+	// A second run finds everything in place
+	changes, err = client.EnsureIndexes(ctx, desired)
+	if err != nil {
+		t.Fatalf("EnsureIndexes failed: %v", err)
+	}
+	if len(changes.Created) != 0 || len(changes.Dropped) != 0 || len(changes.Unchanged) != 3 {
+		t.Errorf("Expected no changes on second run, got %+v", changes)
+	}
+}
+
+func TestEnsureIndexesPrefersExactNames(t *testing.T) {
+	srv := newFakeServer(t)
+	serveIndexes(srv, map[string]interface{}{"name": "y", "keys": wireIndexKeys("email")})
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	for _, desired := range [][]IndexSpec{
+		{
+			{Collection: "users", Name: "a", Keys: []SortSpec{{Field: "email"}}},
+			{Collection: "users", Name: "y", Keys: []SortSpec{{Field: "org"}}},
+		},
+		{
+			{Collection: "users", Name: "y", Keys: []SortSpec{{Field: "org"}}},
+			{Collection: "users", Name: "a", Keys: []SortSpec{{Field: "email"}}},
+		},
+	} {
+		changes, err := client.EnsureIndexes(ctx, desired)
+		if err != nil {
+			t.Fatalf("EnsureIndexes failed: %v", err)
+		}
+		for _, spec := range changes.Unchanged {
+			if spec.Name == "a" {
+				t.Errorf("Expected a to be created rather than kept through y, got %+v", changes)
+			}
+		}
+		serveIndexes(srv, map[string]interface{}{"name": "y", "keys": wireIndexKeys("email")})
+	}
+
+	dup := []IndexSpec{
+		{Collection: "users", Keys: []SortSpec{{Field: "email"}}},
+		{Collection: "users", Keys: []SortSpec{{Field: "email"}}, Unique: true},
+	}
+	if _, err := client.EnsureIndexes(ctx, dup); err == nil {
+		t.Error("Expected an error for two specs with the same index name")
+	}
+}
@@ -0,0 +1,633 @@
+// SquirrelDB Go SDK - Struct Mapping
+
+package squirreldb
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Struct fields are mapped to document data with the `sqrl` tag:
+//
+//	type User struct {
+//		ID        string    `sqrl:"id"`
+//		Email     string    `sqrl:"email"`
+//		Nickname  string    `sqrl:"nickname,omitempty"`
+//		Address   Address   `sqrl:"address"`
+//		Internal  string    `sqrl:"-"`
+//		CreatedAt time.Time `sqrl:"created_at"`
+//		UpdatedAt time.Time `sqrl:"updated_at"`
+//	}
+//
+// Without a sqrl tag the json tag is used, then the field name. A field
+// named "id" (or ID/Id) is bound to Document.Id and must be a string, and
+// fields named
+// created_at/updated_at (or CreatedAt/UpdatedAt) to the document
+// timestamps; none of these are stored in Data. Embedded structs without a
+// tag name are flattened into the parent.
+
+type fieldRole int
+
+const (
+	roleData fieldRole = iota
+	roleID
+	roleCreatedAt
+	roleUpdatedAt
+)
+
+type fieldInfo struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	role      fieldRole
+}
+
+var structFieldCache sync.Map // reflect.Type -> []fieldInfo
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// structFields returns the mapped fields of a struct type
+func structFields(t reflect.Type) []fieldInfo {
+	if cached, ok := structFieldCache.Load(t); ok {
+		return cached.([]fieldInfo)
+	}
+	fields := collectFields(t, nil)
+	structFieldCache.Store(t, fields)
+	return fields
+}
+
+// documentFields returns the mapped fields of a struct type bound to a
+// whole document. The id field must hold a string, since ids are encoded
+// and decoded as the document's string id.
+func documentFields(t reflect.Type) ([]fieldInfo, error) {
+	fields := structFields(t)
+	for _, f := range fields {
+		if f.role != roleID {
+			continue
+		}
+		sf := t.FieldByIndex(f.index)
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.String {
+			return nil, fmt.Errorf("sqrl: id field %s of %s must be a string, got %s", sf.Name, t, sf.Type)
+		}
+	}
+	return fields, nil
+}
+
+func collectFields(t reflect.Type, parent []int) []fieldInfo {
+	var fields []fieldInfo
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+
+		name, opts, tagged := fieldTag(sf)
+		if name == "-" && !tagged {
+			continue
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && ft != timeType {
+			fields = append(fields, collectFields(ft, index)...)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		f := fieldInfo{name: name, index: index}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		for _, opt := range opts {
+			if opt == "omitempty" {
+				f.omitEmpty = true
+			}
+		}
+		f.role = fieldRoleOf(f.name, sf.Name, name != "")
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// fieldTag returns the mapped name and options from the sqrl or json tag.
+// A name of "-" with tagged=false means the field is skipped.
+func fieldTag(sf reflect.StructField) (name string, opts []string, tagged bool) {
+	tag, ok := sf.Tag.Lookup("sqrl")
+	if !ok {
+		tag, ok = sf.Tag.Lookup("json")
+	}
+	if !ok {
+		return "", nil, false
+	}
+	if tag == "-" {
+		return "-", nil, false
+	}
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:], true
+}
+
+func fieldRoleOf(name, goName string, explicit bool) fieldRole {
+	switch name {
+	case "id":
+		return roleID
+	case "created_at":
+		return roleCreatedAt
+	case "updated_at":
+		return roleUpdatedAt
+	}
+	if !explicit {
+		switch goName {
+		case "ID", "Id":
+			return roleID
+		case "CreatedAt":
+			return roleCreatedAt
+		case "UpdatedAt":
+			return roleUpdatedAt
+		}
+	}
+	return roleData
+}
+
+// fieldByIndex returns the field at index, allocating nil embedded pointers
+// when alloc is set. It fails on a nil pointer it may not allocate, which
+// includes pointers to unexported embedded structs.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// ToData converts a struct, or a map with string keys, into document data.
+// The id and timestamp fields are left out; see DocumentFrom to keep them.
+func ToData(v interface{}) (map[string]interface{}, error) {
+	doc, err := DocumentFrom(v)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Data, nil
+}
+
+// DocumentFrom converts a struct into a Document, binding its id and
+// timestamp fields to the document metadata
+func DocumentFrom(v interface{}) (*Document, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("sqrl: cannot map nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+
+	doc := &Document{}
+	switch rv.Kind() {
+	case reflect.Map:
+		data, err := encodeValue(rv, "")
+		if err != nil {
+			return nil, err
+		}
+		m, ok := data.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("sqrl: cannot map %T to document data", v)
+		}
+		doc.Data = m
+		return doc, nil
+	case reflect.Struct:
+	default:
+		return nil, fmt.Errorf("sqrl: cannot map %T to document data", v)
+	}
+
+	fields, err := documentFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	doc.Data = make(map[string]interface{})
+	for _, f := range fields {
+		fv, ok := fieldByIndex(rv, f.index, false)
+		if !ok {
+			continue
+		}
+		switch f.role {
+		case roleID:
+			doc.Id = metadataString(fv)
+			continue
+		case roleCreatedAt:
+			doc.CreatedAt = metadataString(fv)
+			continue
+		case roleUpdatedAt:
+			doc.UpdatedAt = metadataString(fv)
+			continue
+		}
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		val, err := encodeValue(fv, f.name)
+		if err != nil {
+			return nil, err
+		}
+		doc.Data[f.name] = val
+	}
+	return doc, nil
+}
+
+// metadataString formats an id or timestamp field; zero times become ""
+func metadataString(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// encodeValue converts a Go value into its document data representation
+func encodeValue(v reflect.Value, path string) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), nil
+	}
+	if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface &&
+		(v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType)) {
+		raw, err := json.Marshal(v.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("sqrl: field %s: %w", path, err)
+		}
+		var out interface{}
+		json.Unmarshal(raw, &out)
+		return out, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeValue(v.Elem(), path)
+	case reflect.Struct:
+		data := make(map[string]interface{})
+		for _, f := range structFields(v.Type()) {
+			fv, ok := fieldByIndex(v, f.index, false)
+			if !ok || (f.omitEmpty && fv.IsZero()) {
+				continue
+			}
+			val, err := encodeValue(fv, joinPath(path, f.name))
+			if err != nil {
+				return nil, err
+			}
+			data[f.name] = val
+		}
+		return data, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("sqrl: field %s: map keys must be strings", path)
+		}
+		data := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			val, err := encodeValue(iter.Value(), joinPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			data[key] = val
+		}
+		return data, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		fallthrough
+	case reflect.Array:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			val, err := encodeValue(v.Index(i), fmt.Sprintf("%s.%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = val
+		}
+		return items, nil
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return nil, fmt.Errorf("sqrl: field %s: unsupported type %s", path, v.Type())
+	}
+	return v.Interface(), nil
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// FromDocument decodes a document into the struct pointed to by v,
+// including its id and timestamp fields
+func FromDocument(doc *Document, v interface{}) error {
+	if doc == nil {
+		return errors.New("sqrl: cannot decode a nil document")
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("sqrl: decode target must be a non-nil pointer, got %T", v)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return decodeValue(rv, doc.Data, "")
+	}
+
+	fields, err := documentFields(rv.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		var src interface{}
+		switch f.role {
+		case roleID:
+			src = doc.Id
+		case roleCreatedAt:
+			src = doc.CreatedAt
+		case roleUpdatedAt:
+			src = doc.UpdatedAt
+		default:
+			val, ok := doc.Data[f.name]
+			if !ok {
+				continue
+			}
+			src = val
+		}
+		if f.role != roleData && src == "" {
+			continue
+		}
+		fv, ok := fieldByIndex(rv, f.index, true)
+		if !ok {
+			return embeddedPointerError(rv.Type(), f, f.name)
+		}
+		if err := decodeValue(fv, src, f.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// embeddedPointerError reports a field that cannot be decoded because it is
+// promoted through a nil pointer to an unexported embedded struct, as
+// encoding/json does
+func embeddedPointerError(t reflect.Type, f fieldInfo, path string) error {
+	for _, x := range f.index[:len(f.index)-1] {
+		sf := t.Field(x)
+		t = sf.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+			if !sf.IsExported() {
+				break
+			}
+		}
+	}
+	return fmt.Errorf("sqrl: field %s: cannot set embedded pointer to unexported struct %s", path, t)
+}
+
+// decodeValue stores a document data value into dst
+func decodeValue(dst reflect.Value, src interface{}, path string) error {
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return decodeValue(dst.Elem(), src, path)
+	}
+
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(dst.Type()) {
+		dst.Set(sv)
+		return nil
+	}
+
+	if dst.Type() == timeType {
+		s, ok := src.(string)
+		if !ok {
+			return mappingTypeError(path, dst.Type(), src)
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return fmt.Errorf("sqrl: field %s: %w", path, err)
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if dst.CanAddr() {
+		if u, ok := dst.Addr().Interface().(json.Unmarshaler); ok {
+			raw, err := json.Marshal(src)
+			if err != nil {
+				return fmt.Errorf("sqrl: field %s: %w", path, err)
+			}
+			return u.UnmarshalJSON(raw)
+		}
+		if u, ok := dst.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if s, isStr := src.(string); isStr {
+				return u.UnmarshalText([]byte(s))
+			}
+		}
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		if dst.NumMethod() == 0 {
+			dst.Set(sv)
+			return nil
+		}
+	case reflect.String:
+		if s, ok := src.(string); ok {
+			dst.SetString(s)
+			return nil
+		}
+	case reflect.Bool:
+		if b, ok := src.(bool); ok {
+			dst.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, ok := toFloat(src)
+		if n, whole := floatInt64(f); ok && whole && !dst.OverflowInt(n) {
+			dst.SetInt(n)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f, ok := toFloat(src)
+		if ok && f >= 0 && f < 0x1p64 && f == math.Trunc(f) && !dst.OverflowUint(uint64(f)) {
+			dst.SetUint(uint64(f))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, ok := toFloat(src); ok {
+			dst.SetFloat(f)
+			return nil
+		}
+	case reflect.Struct:
+		m, ok := src.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, f := range structFields(dst.Type()) {
+			val, ok := m[f.name]
+			if !ok {
+				continue
+			}
+			fv, ok := fieldByIndex(dst, f.index, true)
+			if !ok {
+				return embeddedPointerError(dst.Type(), f, joinPath(path, f.name))
+			}
+			if err := decodeValue(fv, val, joinPath(path, f.name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		m, ok := src.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			break
+		}
+		out := reflect.MakeMapWithSize(dst.Type(), len(m))
+		for k, val := range m {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := decodeValue(elem, val, joinPath(path, k)); err != nil {
+				return err
+			}
+			out.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(out)
+		return nil
+	case reflect.Slice:
+		if s, ok := src.(string); ok && dst.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return fmt.Errorf("sqrl: field %s: %w", path, err)
+			}
+			dst.SetBytes(b)
+			return nil
+		}
+		items, ok := src.([]interface{})
+		if !ok {
+			break
+		}
+		out := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(out.Index(i), item, fmt.Sprintf("%s.%d", path, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+		return nil
+	case reflect.Array:
+		items, ok := src.([]interface{})
+		if !ok || len(items) > dst.Len() {
+			break
+		}
+		for i, item := range items {
+			if err := decodeValue(dst.Index(i), item, fmt.Sprintf("%s.%d", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if sv.Type().ConvertibleTo(dst.Type()) && sv.Kind() == dst.Kind() {
+		dst.Set(sv.Convert(dst.Type()))
+		return nil
+	}
+	return mappingTypeError(path, dst.Type(), src)
+}
+
+// floatInt64 converts a whole number to int64, reporting false for
+// fractions and values out of range, where the conversion is undefined.
+// The upper bound is exclusive since float64(math.MaxInt64) rounds up to 2^63.
+func floatInt64(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f >= 0x1p63 || f < -0x1p63 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func mappingTypeError(path string, want reflect.Type, got interface{}) error {
+	return fmt.Errorf("sqrl: field %s: cannot decode %T into %s", path, got, want)
+}
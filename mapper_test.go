@@ -0,0 +1,230 @@
+// SquirrelDB Go SDK - Struct Mapping Tests
+
+package squirreldb
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mapperAddress struct {
+	City string `sqrl:"city"`
+	Zip  string `sqrl:"zip,omitempty"`
+}
+
+type mapperAudit struct {
+	Version int `sqrl:"version"`
+}
+
+type mapperUser struct {
+	mapperAudit
+	ID        string          `sqrl:"id"`
+	Email     string          `sqrl:"email"`
+	Nickname  string          `sqrl:"nickname,omitempty"`
+	Address   mapperAddress   `sqrl:"address"`
+	Previous  []mapperAddress `sqrl:"previous"`
+	Tags      []string        `sqrl:"tags"`
+	Manager   *mapperAddress  `sqrl:"manager,omitempty"`
+	Score     float64         `json:"score"`
+	Internal  string          `sqrl:"-"`
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt *time.Time `sqrl:"updated_at"`
+}
+
+func TestDocumentFromStruct(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	doc, err := DocumentFrom(&mapperUser{
+		mapperAudit: mapperAudit{Version: 3},
+		ID:          "u1",
+		Email:       "a@example.com",
+		Address:     mapperAddress{City: "Paris"},
+		Previous:    []mapperAddress{{City: "Lyon", Zip: "69000"}},
+		Tags:        []string{"admin"},
+		Score:       4.5,
+		Internal:    "secret",
+		Active:      true,
+		CreatedAt:   created,
+	})
+	if err != nil {
+		t.Fatalf("DocumentFrom failed: %v", err)
+	}
+
+	if doc.Id != "u1" {
+		t.Errorf("Expected id 'u1', got '%s'", doc.Id)
+	}
+	if doc.CreatedAt != "2024-01-02T03:04:05Z" {
+		t.Errorf("Expected created_at to be formatted, got '%s'", doc.CreatedAt)
+	}
+	if doc.UpdatedAt != "" {
+		t.Errorf("Expected empty updated_at, got '%s'", doc.UpdatedAt)
+	}
+
+	expected := map[string]interface{}{
+		"version":  3,
+		"email":    "a@example.com",
+		"address":  map[string]interface{}{"city": "Paris"},
+		"previous": []interface{}{map[string]interface{}{"city": "Lyon", "zip": "69000"}},
+		"tags":     []interface{}{"admin"},
+		"score":    4.5,
+		"Active":   true,
+	}
+	if !reflect.DeepEqual(doc.Data, expected) {
+		t.Errorf("Unexpected data:\n got %#v\nwant %#v", doc.Data, expected)
+	}
+}
+
+func TestFromDocumentDecodesJSONData(t *testing.T) {
+	raw := `{
+		"id": "u1",
+		"collection": "users",
+		"data": {
+			"version": 2,
+			"email": "a@example.com",
+			"address": {"city": "Paris", "zip": "75001"},
+			"previous": [{"city": "Lyon"}],
+			"tags": ["a", "b"],
+			"manager": {"city": "Nice"},
+			"score": 9,
+			"Active": true
+		},
+		"created_at": "2024-01-02T03:04:05Z",
+		"updated_at": "2024-02-02T03:04:05Z"
+	}`
+	var doc Document
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		t.Fatalf("Failed to unmarshal document: %v", err)
+	}
+
+	var u mapperUser
+	if err := FromDocument(&doc, &u); err != nil {
+		t.Fatalf("FromDocument failed: %v", err)
+	}
+	if u.ID != "u1" || u.Version != 2 || u.Email != "a@example.com" {
+		t.Errorf("Unexpected scalar fields: %+v", u)
+	}
+	if u.Address.Zip != "75001" || len(u.Previous) != 1 || u.Previous[0].City != "Lyon" {
+		t.Errorf("Unexpected nested fields: %+v", u)
+	}
+	if u.Manager == nil || u.Manager.City != "Nice" {
+		t.Errorf("Expected manager pointer to be set, got %+v", u.Manager)
+	}
+	if len(u.Tags) != 2 || u.Score != 9 || !u.Active {
+		t.Errorf("Unexpected fields: %+v", u)
+	}
+	if u.CreatedAt.Year() != 2024 || u.UpdatedAt == nil || u.UpdatedAt.Month() != 2 {
+		t.Errorf("Expected timestamps to be bound, got %v / %v", u.CreatedAt, u.UpdatedAt)
+	}
+}
+
+func TestFromDocumentTypeMismatch(t *testing.T) {
+	doc := &Document{Data: map[string]interface{}{"address": map[string]interface{}{"city": 12}}}
+	var u mapperUser
+	err := FromDocument(doc, &u)
+	if err == nil {
+		t.Fatal("Expected type mismatch error")
+	}
+	if want := "sqrl: field address.city: cannot decode int into string"; err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestFromDocumentRejectsFractionalInt(t *testing.T) {
+	doc := &Document{Data: map[string]interface{}{"version": 1.5}}
+	var u mapperUser
+	if err := FromDocument(doc, &u); err == nil {
+		t.Error("Expected error decoding 1.5 into int")
+	}
+}
+
+func TestFromDocumentRejectsOverflow(t *testing.T) {
+	tests := []struct {
+		value  float64
+		target interface{}
+	}{
+		{1e19, new(int64)},
+		{-1e19, new(int64)},
+		{0x1p63, new(int64)},
+		{0x1p64, new(uint64)},
+		{300, new(int8)},
+		{-1, new(uint)},
+	}
+	for _, tt := range tests {
+		target := reflect.New(reflect.StructOf([]reflect.StructField{{
+			Name: "V", Type: reflect.TypeOf(tt.target).Elem(), Tag: `sqrl:"v"`,
+		}}))
+		doc := &Document{Data: map[string]interface{}{"v": tt.value}}
+		if err := FromDocument(doc, target.Interface()); err == nil {
+			t.Errorf("Expected error decoding %g into %s, got %v", tt.value, target.Elem().Field(0).Type(), target.Elem().Field(0))
+		}
+	}
+
+	var max struct {
+		V uint64 `sqrl:"v"`
+	}
+	if err := FromDocument(&Document{Data: map[string]interface{}{"v": 0x1p63}}, &max); err != nil || max.V != 1<<63 {
+		t.Errorf("Expected 2^63 to fit a uint64, got %d (%v)", max.V, err)
+	}
+}
+
+type mapperBase struct {
+	Source string `sqrl:"source"`
+}
+
+type mapperOuter struct {
+	*mapperBase
+	Age int `sqrl:"age"`
+}
+
+func TestFromDocumentUnexportedEmbeddedPointer(t *testing.T) {
+	doc := &Document{Data: map[string]interface{}{"source": "import", "age": 30}}
+
+	var out mapperOuter
+	err := FromDocument(doc, &out)
+	if err == nil || err.Error() != "sqrl: field source: cannot set embedded pointer to unexported struct squirreldb.mapperBase" {
+		t.Errorf("Expected embedded pointer error, got %v", err)
+	}
+
+	out = mapperOuter{mapperBase: &mapperBase{}}
+	if err := FromDocument(doc, &out); err != nil || out.Source != "import" || out.Age != 30 {
+		t.Errorf("Expected allocated embedded pointer to decode, got %+v (%v)", out, err)
+	}
+
+	data, err := ToData(mapperOuter{Age: 30})
+	if err != nil || !reflect.DeepEqual(data, map[string]interface{}{"age": 30}) {
+		t.Errorf("Expected nil embedded pointer to be skipped, got %v (%v)", data, err)
+	}
+}
+
+func TestToDataRejectsNonStruct(t *testing.T) {
+	if _, err := ToData(42); err == nil {
+		t.Error("Expected error mapping an int")
+	}
+	data, err := ToData(map[string]int{"a": 1})
+	if err != nil || data["a"] != 1 {
+		t.Errorf("Expected map to be converted, got %v (%v)", data, err)
+	}
+}
+
+func TestMapperRejectsInvalidDocuments(t *testing.T) {
+	var user struct {
+		ID   string `sqrl:"id"`
+		Name string `sqrl:"name"`
+	}
+	if err := FromDocument(nil, &user); err == nil {
+		t.Error("Expected error for a nil document")
+	}
+
+	var numbered struct {
+		ID   int    `sqrl:"id"`
+		Name string `sqrl:"name"`
+	}
+	if _, err := DocumentFrom(numbered); err == nil {
+		t.Error("Expected error encoding a non-string id field")
+	}
+	if err := FromDocument(&Document{Id: "7"}, &numbered); err == nil {
+		t.Error("Expected error decoding into a non-string id field")
+	}
+}
@@ -0,0 +1,272 @@
+// SquirrelDB Go SDK - Offline Write Queue
+
+package squirreldb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrQueued is returned by mutations stored in the offline queue instead of
+// being sent. The outcome is reported to OfflineQueueOptions.OnReplay.
+var ErrQueued = errors.New("mutation queued for replay")
+
+// ErrQueueFull is returned when the offline queue has reached its MaxSize
+var ErrQueueFull = errors.New("offline queue full")
+
+// QueuedMutation is a write waiting in the offline queue. Numbers in
+// Message are json.Number values.
+type QueuedMutation struct {
+	Seq        int64                  `json:"seq"`
+	QueuedAt   time.Time              `json:"queued_at"`
+	Type       string                 `json:"type"`
+	Collection string                 `json:"collection"`
+	DocumentID string                 `json:"document_id,omitempty"`
+	Message    map[string]interface{} `json:"message"`
+}
+
+// OfflineQueueOptions configures an offline queue
+type OfflineQueueOptions struct {
+	// MaxSize limits the number of queued mutations; 0 means unlimited
+	MaxSize int
+	// OnReplay is called once per queued mutation with the outcome of its replay
+	OnReplay func(op QueuedMutation, doc *Document, err error)
+}
+
+// OfflineQueue is a write-ahead log of mutations persisted to a local file.
+// Mutations are replayed in order with their original idempotency keys.
+type OfflineQueue struct {
+	path    string
+	opts    OfflineQueueOptions
+	mu      sync.Mutex
+	replay  sync.Mutex
+	entries []QueuedMutation
+	nextSeq int64
+}
+
+// OpenOfflineQueue opens the queue stored at path, creating it if needed
+func OpenOfflineQueue(path string, opts *OfflineQueueOptions) (*OfflineQueue, error) {
+	q := &OfflineQueue{path: path, nextSeq: 1}
+	if opts != nil {
+		q.opts = *opts
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), MaxMessageSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var op QueuedMutation
+		if err := decodeQueued(scanner.Bytes(), &op); err != nil {
+			return nil, fmt.Errorf("offline queue %s: %w", path, err)
+		}
+		q.entries = append(q.entries, op)
+		if op.Seq >= q.nextSeq {
+			q.nextSeq = op.Seq + 1
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("offline queue %s: %w", path, err)
+	}
+	return q, nil
+}
+
+// Len returns the number of queued mutations
+func (q *OfflineQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.entries)
+}
+
+// Pending returns a copy of the queued mutations in replay order
+func (q *OfflineQueue) Pending() []QueuedMutation {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]QueuedMutation(nil), q.entries...)
+}
+
+// enqueue appends a mutation message and persists it, returning ErrQueued on success
+func (q *OfflineQueue) enqueue(msg map[string]interface{}) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.opts.MaxSize > 0 && len(q.entries) >= q.opts.MaxSize {
+		return ErrQueueFull
+	}
+
+	stored := make(map[string]interface{}, len(msg))
+	for k, v := range msg {
+		if k != "id" {
+			stored[k] = v
+		}
+	}
+	op := QueuedMutation{
+		Seq:      q.nextSeq,
+		QueuedAt: time.Now().UTC(),
+		Message:  stored,
+	}
+	op.Type, _ = msg["type"].(string)
+	op.Collection, _ = msg["collection"].(string)
+	op.DocumentID, _ = msg["document_id"].(string)
+
+	line, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	// Keep what was written rather than the caller's maps, which the caller
+	// may reuse once the mutation is queued
+	op = QueuedMutation{}
+	if err := decodeQueued(line, &op); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(q.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	q.nextSeq++
+	q.entries = append(q.entries, op)
+	return ErrQueued
+}
+
+// decodeQueued decodes a queue file line, keeping numbers as json.Number so
+// integers beyond float64 precision replay unchanged
+func decodeQueued(line []byte, op *QueuedMutation) error {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	return dec.Decode(op)
+}
+
+// remove drops the head of the queue and rewrites the file
+func (q *OfflineQueue) remove(seq int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.entries) == 0 || q.entries[0].Seq != seq {
+		return nil
+	}
+	q.entries = q.entries[1:]
+	return q.persist()
+}
+
+// persist atomically rewrites the queue file; the caller must hold q.mu
+func (q *OfflineQueue) persist() error {
+	tmp := q.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, op := range q.entries {
+		line, err := json.Marshal(op)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		w.Write(line)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, q.path)
+}
+
+// Replay sends queued mutations through c in order. Mutations rejected by
+// the server are dropped and reported to OnReplay; replay stops at the first
+// other failure, such as a lost connection, a rate limit or a retryable
+// server error, leaving the rest queued for the next attempt. A client
+// that is connected makes that attempt on its next write.
+func (q *OfflineQueue) Replay(ctx context.Context, c *Client) error {
+	q.replay.Lock()
+	defer q.replay.Unlock()
+
+	for {
+		q.mu.Lock()
+		if len(q.entries) == 0 {
+			q.mu.Unlock()
+			return nil
+		}
+		op := q.entries[0]
+		q.mu.Unlock()
+
+		msg := make(map[string]interface{}, len(op.Message))
+		for k, v := range op.Message {
+			msg[k] = v
+		}
+		result, err := c.request(ctx, msg)
+		if err != nil && (!isRejection(err) || ctx.Err() != nil) {
+			return err
+		}
+		if rmErr := q.remove(op.Seq); rmErr != nil {
+			return rmErr
+		}
+
+		var doc *Document
+		if err == nil {
+			doc = firstDocument(result)
+		}
+		if q.opts.OnReplay != nil {
+			q.opts.OnReplay(op, doc, err)
+		}
+	}
+}
+
+// isRejection reports whether err is a definitive refusal by the server, so
+// sending the same request again cannot succeed
+func isRejection(err error) bool {
+	var serverErr *ServerError
+	return errors.As(err, &serverErr) && !IsRetryable(err)
+}
+
+// isDisconnected reports whether err means the request could not reach the server
+func isDisconnected(err error) bool {
+	if errors.Is(err, ErrClosed) || errors.Is(err, ErrNotConnected) ||
+		errors.Is(err, ErrCircuitOpen) || errors.Is(err, io.EOF) ||
+		errors.Is(err, websocket.ErrCloseSent) || errors.Is(err, net.ErrClosed) {
+		return true
+	}
+	var netErr net.Error
+	var closeErr *websocket.CloseError
+	return errors.As(err, &netErr) || errors.As(err, &closeErr)
+}
@@ -0,0 +1,259 @@
+// SquirrelDB Go SDK - Offline Write Queue Tests
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOfflineQueuePersistsAcrossOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+	q, err := OpenOfflineQueue(path, nil)
+	if err != nil {
+		t.Fatalf("Failed to open queue: %v", err)
+	}
+
+	err = q.enqueue(map[string]interface{}{"type": "Insert", "id": "req-1", "collection": "users"})
+	if !errors.Is(err, ErrQueued) {
+		t.Fatalf("Expected ErrQueued, got %v", err)
+	}
+	q.enqueue(map[string]interface{}{"type": "Delete", "collection": "users", "document_id": "doc-1"})
+
+	reopened, err := OpenOfflineQueue(path, nil)
+	if err != nil {
+		t.Fatalf("Failed to reopen queue: %v", err)
+	}
+	pending := reopened.Pending()
+	if len(pending) != 2 {
+		t.Fatalf("Expected 2 pending mutations, got %d", len(pending))
+	}
+	if pending[0].Type != "Insert" || pending[1].DocumentID != "doc-1" {
+		t.Errorf("Unexpected pending mutations: %+v", pending)
+	}
+	if _, ok := pending[0].Message["id"]; ok {
+		t.Error("Expected request id to be stripped from queued message")
+	}
+	if pending[1].Seq <= pending[0].Seq {
+		t.Error("Expected increasing sequence numbers")
+	}
+}
+
+func TestOfflineQueueMaxSize(t *testing.T) {
+	q, _ := OpenOfflineQueue(filepath.Join(t.TempDir(), "queue.log"), &OfflineQueueOptions{MaxSize: 1})
+	q.enqueue(map[string]interface{}{"type": "Insert"})
+	if err := q.enqueue(map[string]interface{}{"type": "Insert"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+}
+
+func TestClientQueuesWhileDisconnectedAndReplaysInOrder(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Insert", func(msg map[string]interface{}) map[string]interface{} {
+		return documentsReply(map[string]interface{}{"id": "doc-1", "collection": "users"})
+	})
+	srv.handle("Delete", func(msg map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "Error", "code": "not_found", "message": "missing"}
+	})
+
+	var mu sync.Mutex
+	var outcomes []string
+	path := filepath.Join(t.TempDir(), "queue.log")
+	q, err := OpenOfflineQueue(path, &OfflineQueueOptions{
+		OnReplay: func(op QueuedMutation, doc *Document, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				outcomes = append(outcomes, op.Type+":error")
+			} else {
+				outcomes = append(outcomes, op.Type+":"+doc.Id)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to open queue: %v", err)
+	}
+
+	client := srv.connect(&Options{OfflineQueue: q})
+	client.Close()
+
+	ctx := context.Background()
+	if _, err := client.Insert(ctx, "users", map[string]interface{}{"name": "Alice"}); !errors.Is(err, ErrQueued) {
+		t.Fatalf("Expected ErrQueued, got %v", err)
+	}
+	if _, err := client.Delete(ctx, "users", "doc-9"); !errors.Is(err, ErrQueued) {
+		t.Fatalf("Expected ErrQueued, got %v", err)
+	}
+	key := q.Pending()[0].Message["idempotency_key"]
+
+	if err := client.Reconnect(ctx); err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+
+	if q.Len() != 0 {
+		t.Errorf("Expected empty queue after replay, got %d", q.Len())
+	}
+	mu.Lock()
+	if len(outcomes) != 2 || outcomes[0] != "Insert:doc-1" || outcomes[1] != "Delete:error" {
+		t.Errorf("Unexpected replay outcomes: %v", outcomes)
+	}
+	mu.Unlock()
+	if inserts := srv.messages("Insert"); len(inserts) != 1 || inserts[0]["idempotency_key"] != key {
+		t.Errorf("Expected replayed insert with original idempotency key, got %v", inserts)
+	}
+
+	reopened, _ := OpenOfflineQueue(path, nil)
+	if reopened.Len() != 0 {
+		t.Errorf("Expected empty queue file after replay, got %d entries", reopened.Len())
+	}
+}
+
+func TestWriteResumesStalledReplay(t *testing.T) {
+	srv := newFakeServer(t)
+	var unavailable atomic.Bool
+	srv.handle("Insert", func(msg map[string]interface{}) map[string]interface{} {
+		if unavailable.Load() {
+			return map[string]interface{}{"type": "Error", "code": CodeUnavailable, "message": "try later", "retryable": true}
+		}
+		return documentsReply(map[string]interface{}{"id": "doc-1", "collection": "users", "data": msg["data"]})
+	})
+	q, _ := OpenOfflineQueue(filepath.Join(t.TempDir(), "queue.log"), nil)
+	client := srv.connect(&Options{OfflineQueue: q})
+	client.Close()
+
+	ctx := context.Background()
+	if _, err := client.Insert(ctx, "users", map[string]interface{}{"name": "Alice"}); !errors.Is(err, ErrQueued) {
+		t.Fatalf("Expected ErrQueued, got %v", err)
+	}
+	unavailable.Store(true)
+	if err := client.Reconnect(ctx); err == nil {
+		t.Fatal("Expected replay to stop on the unavailable error")
+	}
+
+	unavailable.Store(false)
+	if _, err := client.Insert(ctx, "users", map[string]interface{}{"name": "Bob"}); err != nil {
+		t.Fatalf("Expected the connected write to be sent after resuming replay, got %v", err)
+	}
+	if q.Len() != 0 {
+		t.Errorf("Expected queue to drain, got %d entries", q.Len())
+	}
+	inserts := srv.messages("Insert")
+	if last := inserts[len(inserts)-1]["data"].(map[string]interface{}); last["name"] != "Bob" {
+		t.Errorf("Expected the new write after the replayed one, got %v", last)
+	}
+}
+
+func TestOfflineQueueKeepsLargeIntegers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+	q, _ := OpenOfflineQueue(path, nil)
+	q.enqueue(map[string]interface{}{"type": "Insert", "data": map[string]interface{}{"n": int64(1<<53 + 1)}})
+
+	reopened, _ := OpenOfflineQueue(path, nil)
+	for _, queue := range []*OfflineQueue{q, reopened} {
+		data := queue.Pending()[0].Message["data"].(map[string]interface{})
+		if n, ok := data["n"].(json.Number); !ok || n.String() != "9007199254740993" {
+			t.Errorf("Expected the integer to survive queueing, got %#v", data["n"])
+		}
+	}
+}
+
+func TestBulkReportsQueuedItems(t *testing.T) {
+	srv := newFakeServer(t)
+	q, _ := OpenOfflineQueue(filepath.Join(t.TempDir(), "queue.log"), nil)
+	client := srv.connect(&Options{OfflineQueue: q})
+	client.Close()
+
+	result, err := client.InsertMany(context.Background(), "users", []map[string]interface{}{{"name": "Alice"}, {"name": "Bob"}})
+	if err != nil {
+		t.Fatalf("Expected queued items not to fail the bulk insert, got %v", err)
+	}
+	if len(result.Queued()) != 2 || len(result.Failed()) != 0 || len(result.Succeeded()) != 0 {
+		t.Errorf("Expected 2 queued items, got %+v", result.Items)
+	}
+	if q.Len() != 1 || q.Pending()[0].Type != "InsertMany" {
+		t.Errorf("Expected the batch to be queued as one mutation, got %+v", q.Pending())
+	}
+}
+
+func TestConnectWithOfflineQueueStartsDisconnected(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	q, _ := OpenOfflineQueue(filepath.Join(t.TempDir(), "queue.log"), nil)
+	client, err := Connect(context.Background(), &Options{Host: "127.0.0.1", Port: port, OfflineQueue: q})
+	if err != nil {
+		t.Fatalf("Expected disconnected client, got error %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Insert(context.Background(), "users", map[string]interface{}{}); !errors.Is(err, ErrQueued) {
+		t.Errorf("Expected ErrQueued, got %v", err)
+	}
+}
+
+func TestReplayKeepsMutationOnTransientFailure(t *testing.T) {
+	srv := newFakeServer(t)
+	var unavailable atomic.Bool
+	unavailable.Store(true)
+	srv.handle("Insert", func(msg map[string]interface{}) map[string]interface{} {
+		if unavailable.Load() {
+			return map[string]interface{}{"type": "Error", "code": CodeUnavailable, "message": "try later", "retryable": true}
+		}
+		return documentsReply(map[string]interface{}{"id": "doc-1", "collection": "users", "data": msg["data"]})
+	})
+
+	q, err := OpenOfflineQueue(filepath.Join(t.TempDir(), "queue.log"), nil)
+	if err != nil {
+		t.Fatalf("Failed to open queue: %v", err)
+	}
+	limiter := NewRateLimiter(0, 1)
+	limiter.FailFast = true
+	client := srv.connect(&Options{OfflineQueue: q, RateLimits: &RateLimits{Insert: limiter}})
+	client.Close()
+
+	ctx := context.Background()
+	data := map[string]interface{}{"name": "Alice"}
+	if _, err := client.Insert(ctx, "users", data); !errors.Is(err, ErrQueued) {
+		t.Fatalf("Expected ErrQueued, got %v", err)
+	}
+	// The caller may reuse its map once the write is queued
+	data["name"] = "Mallory"
+
+	// The only token is spent on the retryable error, then the limiter refuses
+	var serverErr *ServerError
+	if err := client.Reconnect(ctx); !errors.As(err, &serverErr) || serverErr.Code != CodeUnavailable {
+		t.Errorf("Expected unavailable error from replay, got %v", err)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("Expected mutation to stay queued after a retryable error, got %d entries", q.Len())
+	}
+	if err := client.Reconnect(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited from replay, got %v", err)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("Expected mutation to stay queued when rate limited, got %d entries", q.Len())
+	}
+
+	unavailable.Store(false)
+	client.rateLimits = nil
+	if err := client.Reconnect(ctx); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if q.Len() != 0 {
+		t.Errorf("Expected queue to drain, got %d entries", q.Len())
+	}
+	inserts := srv.messages("Insert")
+	if last := inserts[len(inserts)-1]["data"].(map[string]interface{}); last["name"] != "Alice" {
+		t.Errorf("Expected the queued value to be replayed, got %v", last)
+	}
+}
@@ -167,6 +167,14 @@ func (q *QueryBuilder) Changes(opts *ChangesOptions) *QueryBuilder {
 	return q
 }
 
+// clone returns a copy of the builder that can be modified independently
+func (q *QueryBuilder) clone() *QueryBuilder {
+	c := *q
+	c.filters = append([]FilterCondition(nil), q.filters...)
+	c.sorts = append([]SortSpec(nil), q.sorts...)
+	return &c
+}
+
 // CompileStructured returns the structured query object
 func (q *QueryBuilder) CompileStructured() StructuredQuery {
 	query := StructuredQuery{
@@ -0,0 +1,193 @@
+// SquirrelDB Go SDK - Rate Limiting
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by fail-fast rate limiters when no token is available
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimiter is a token bucket allowing a sustained rate with bursts.
+// A single limiter may be shared between clients to enforce a common quota.
+type RateLimiter struct {
+	// FailFast makes calls fail with ErrRateLimited instead of waiting for a token
+	FailFast bool
+
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewRateLimiter creates a limiter refilling perSecond tokens per second
+// and holding at most burst tokens. The bucket starts full. A limiter with
+// perSecond <= 0 never refills, so once the burst is spent even waiting
+// calls fail with ErrRateLimited.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// refill adds tokens accrued since the last call; the caller must hold l.mu
+func (l *RateLimiter) refill() {
+	now := l.now()
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+elapsed*l.rate)
+	}
+	l.last = now
+}
+
+// Allow takes a token if one is available without waiting
+func (l *RateLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if l.tokens >= 1 {
+		l.tokens--
+		return true
+	}
+	return false
+}
+
+// Wait blocks until a token is available or ctx is done. It returns
+// ErrRateLimited without waiting if the limiter never refills.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.refill()
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return ErrRateLimited
+	}
+	// Reserve the next token and sleep until it has accrued
+	delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	l.tokens--
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the reservation back without overfilling a refilled bucket
+		l.mu.Lock()
+		l.tokens = math.Min(l.burst, l.tokens+1)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// acquire takes a token according to the limiter's mode. A nil limiter never limits.
+func (l *RateLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if l.FailFast {
+		if !l.Allow() {
+			return ErrRateLimited
+		}
+		return nil
+	}
+	return l.Wait(ctx)
+}
+
+// RateLimits assigns rate limiters to client operations; nil entries are
+// unlimited. Limits also apply to the bulk variant of each operation,
+// Query also covers aggregations and Update covers UpdateWith, Upsert and
+// Replace. Transaction control (Begin, Commit, Rollback), collection and
+// index administration and pings are not limited; writes inside a
+// transaction count against their operation.
+type RateLimits struct {
+	Query     *RateLimiter
+	Insert    *RateLimiter
+	Update    *RateLimiter
+	Delete    *RateLimiter
+	Subscribe *RateLimiter
+}
+
+// forMessage returns the limiter for a request message type
+func (r *RateLimits) forMessage(msgType string) *RateLimiter {
+	if r == nil {
+		return nil
+	}
+	switch msgType {
+	case "Query", "Aggregate":
+		return r.Query
+	case "Insert", "InsertMany":
+		return r.Insert
+	case "Update", "UpdateMany", "Upsert", "Replace":
+		return r.Update
+	case "Delete", "DeleteMany":
+		return r.Delete
+	case "Subscribe":
+		return r.Subscribe
+	}
+	return nil
+}
+
+// CacheRateLimits assigns rate limiters to cache commands; nil entries are
+// unlimited. Delete covers DEL and FLUSHDB, Write covers SET, MSET, EXPIRE
+// and the counters, and Read covers every other command.
+type CacheRateLimits struct {
+	Read   *RateLimiter
+	Write  *RateLimiter
+	Delete *RateLimiter
+}
+
+// forCommand returns the limiter for a cache command
+func (r *CacheRateLimits) forCommand(cmd string) *RateLimiter {
+	if r == nil {
+		return nil
+	}
+	switch cmd {
+	case "DEL", "FLUSHDB":
+		return r.Delete
+	case "SET", "MSET", "EXPIRE", "INCR", "DECR", "INCRBY", "DECRBY":
+		return r.Write
+	}
+	return r.Read
+}
+
+// StorageRateLimits assigns rate limiters to storage requests by HTTP
+// method; nil entries are unlimited. Read covers GET and HEAD, Write covers
+// PUT and Delete covers DELETE.
+type StorageRateLimits struct {
+	Read   *RateLimiter
+	Write  *RateLimiter
+	Delete *RateLimiter
+}
+
+// forMethod returns the limiter for a storage request method
+func (r *StorageRateLimits) forMethod(method string) *RateLimiter {
+	if r == nil {
+		return nil
+	}
+	switch method {
+	case "PUT", "POST":
+		return r.Write
+	case "DELETE":
+		return r.Delete
+	}
+	return r.Read
+}
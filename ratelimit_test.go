@@ -0,0 +1,160 @@
+// SquirrelDB Go SDK - Rate Limiting Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurstAndRefill(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(2, 2)
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	if !l.Allow() || !l.Allow() {
+		t.Fatal("Expected burst of 2 to be allowed")
+	}
+	if l.Allow() {
+		t.Fatal("Expected third call to be denied")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if !l.Allow() {
+		t.Error("Expected a token after refill")
+	}
+	if l.Allow() {
+		t.Error("Expected bucket to be empty again")
+	}
+}
+
+func TestRateLimiterWaitHonoursContext(t *testing.T) {
+	l := NewRateLimiter(0.001, 1)
+	l.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRateLimiterCancelledWaitDoesNotOverfill(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(1, 1)
+	l.now = func() time.Time { return now }
+	l.last = now
+	l.Allow()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- l.Wait(ctx) }()
+	for {
+		l.mu.Lock()
+		reserved := l.tokens < 0
+		l.mu.Unlock()
+		if reserved {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The bucket refills completely before the waiter gives up
+	l.mu.Lock()
+	now = now.Add(10 * time.Second)
+	l.refill()
+	l.mu.Unlock()
+	cancel()
+	<-done
+
+	if !l.Allow() {
+		t.Fatal("Expected a token after refill")
+	}
+	if l.Allow() {
+		t.Error("Expected the refund not to exceed the burst")
+	}
+}
+
+func TestRateLimiterWaitBlocksUntilToken(t *testing.T) {
+	l := NewRateLimiter(100, 1)
+	l.Allow()
+
+	start := time.Now()
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 5*time.Millisecond {
+		t.Errorf("Expected Wait to block for a token, returned after %v", elapsed)
+	}
+}
+
+func TestClientRateLimitFailFast(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Insert", func(msg map[string]interface{}) map[string]interface{} {
+		return documentsReply(map[string]interface{}{"id": "doc-1"})
+	})
+	limiter := NewRateLimiter(0.001, 1)
+	limiter.FailFast = true
+	client := srv.connect(&Options{RateLimits: &RateLimits{Insert: limiter}})
+
+	ctx := context.Background()
+	if _, err := client.Insert(ctx, "users", map[string]interface{}{}); err != nil {
+		t.Fatalf("First insert failed: %v", err)
+	}
+	if _, err := client.Insert(ctx, "users", map[string]interface{}{}); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+	if _, err := client.Query(ctx, `db.table("users").run()`); errors.Is(err, ErrRateLimited) {
+		t.Error("Expected queries to be unaffected by the insert limiter")
+	}
+	if n := len(srv.messages("Insert")); n != 1 {
+		t.Errorf("Expected 1 insert to reach the server, got %d", n)
+	}
+}
+
+func TestStorageRateLimitsPerMethod(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer srv.Close()
+
+	write := NewRateLimiter(0.001, 1)
+	write.Allow()
+	storage := ConnectStorage(&StorageOptions{Endpoint: srv.URL, RateLimits: &StorageRateLimits{Write: write}})
+
+	if _, err := storage.GetObject("b", "k"); err != nil {
+		t.Fatalf("Expected reads to be unaffected by the write limiter, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := storage.WithContext(ctx).PutObject("b", "k", []byte("v"), ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the token wait to end with the context, got %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("Expected 1 request to reach the server, got %d", n)
+	}
+}
+
+func TestCacheRateLimitsForCommand(t *testing.T) {
+	limits := &CacheRateLimits{Read: NewRateLimiter(1, 1), Write: NewRateLimiter(1, 1), Delete: NewRateLimiter(1, 1)}
+	for cmd, want := range map[string]*RateLimiter{
+		"GET": limits.Read, "PING": limits.Read, "SET": limits.Write,
+		"INCRBY": limits.Write, "DEL": limits.Delete, "FLUSHDB": limits.Delete,
+	} {
+		if got := limits.forCommand(cmd); got != want {
+			t.Errorf("%s: unexpected limiter", cmd)
+		}
+	}
+	var unlimited *CacheRateLimits
+	if unlimited.forCommand("GET") != nil {
+		t.Error("Expected no limiter without rate limits")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 )
 
 // RESP protocol errors
@@ -16,6 +17,50 @@ var (
 	ErrProtocolError   = errors.New("RESP protocol error")
 )
 
+// CacheError is an error reply from the cache server, e.g. "-WRONGTYPE ..."
+type CacheError struct {
+	// Prefix is the leading error kind, e.g. ERR, WRONGTYPE or NOPERM
+	Prefix  string
+	Message string
+}
+
+func (e *CacheError) Error() string {
+	if e.Message == "" {
+		return e.Prefix
+	}
+	return e.Prefix + " " + e.Message
+}
+
+// Is matches the error category sentinels (ErrPermissionDenied, ErrValidation)
+func (e *CacheError) Is(target error) bool {
+	switch target {
+	case ErrPermissionDenied:
+		return e.Prefix == "NOPERM" || e.Prefix == "NOAUTH" || e.Prefix == "WRONGPASS"
+	case ErrValidation:
+		return e.Prefix == "WRONGTYPE"
+	}
+	return false
+}
+
+// Retryable reports whether the command may succeed if retried
+func (e *CacheError) Retryable() bool {
+	switch e.Prefix {
+	case "LOADING", "BUSY", "TRYAGAIN", "CLUSTERDOWN", "MASTERDOWN":
+		return true
+	}
+	return false
+}
+
+// parseCacheError splits a RESP error line (without the '-') into prefix and message
+func parseCacheError(line string) *CacheError {
+	prefix, message, _ := strings.Cut(line, " ")
+	if prefix != strings.ToUpper(prefix) {
+		// No error kind, the whole line is the message
+		return &CacheError{Prefix: "ERR", Message: line}
+	}
+	return &CacheError{Prefix: prefix, Message: message}
+}
+
 // RESP type prefixes
 const (
 	respSimpleString = '+'
@@ -75,7 +120,7 @@ func readResp(r *bufio.Reader) (RespValue, error) {
 		return RespValue{Type: respSimpleString, Str: data}, nil
 
 	case respError:
-		return RespValue{Type: respError, Err: errors.New(data)}, nil
+		return RespValue{Type: respError, Err: parseCacheError(data)}, nil
 
 	case respInteger:
 		n, err := strconv.ParseInt(data, 10, 64)
@@ -0,0 +1,128 @@
+// SquirrelDB Go SDK - Retry Policy Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type retryableErr struct{ retry bool }
+
+func (e retryableErr) Error() string   { return "transient" }
+func (e retryableErr) Retryable() bool { return e.retry }
+
+func TestIsRetryable(t *testing.T) {
+	if IsRetryable(nil) {
+		t.Error("Expected nil to be non-retryable")
+	}
+	if IsRetryable(errors.New("boom")) {
+		t.Error("Expected plain error to be non-retryable")
+	}
+	if !IsRetryable(retryableErr{retry: true}) {
+		t.Error("Expected error opting in to be retryable")
+	}
+	if IsRetryable(retryableErr{retry: false}) {
+		t.Error("Expected error opting out to be non-retryable")
+	}
+	if IsRetryable(context.DeadlineExceeded) {
+		t.Error("Expected context errors to be non-retryable")
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 35 * time.Millisecond, Multiplier: 2}
+	expected := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 35 * time.Millisecond}
+	for i, want := range expected {
+		if got := p.backoff(i + 1); got != want {
+			t.Errorf("Retry %d: expected backoff %v, got %v", i+1, want, got)
+		}
+	}
+}
+
+func TestRetryPolicyStopsOnNonRetryable(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 5}
+	calls := 0
+	err := p.do(context.Background(), func() error {
+		calls++
+		return errors.New("permanent")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("Expected one failed attempt, got %d (err=%v)", calls, err)
+	}
+}
+
+func TestRetryPolicyStopsWhenContextEnds(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	busy := retryableErr{retry: true}
+	calls := 0
+	err := p.do(ctx, func() error {
+		calls++
+		cancel()
+		return busy
+	})
+	if calls != 1 || !errors.Is(err, context.Canceled) || !errors.Is(err, busy) {
+		t.Errorf("Expected cancellation joined with the last error after one attempt, got %d (err=%v)", calls, err)
+	}
+}
+
+func TestInsertRetriesWithSameIdempotencyKey(t *testing.T) {
+	srv := newFakeServer(t)
+	var attempts atomic.Int32
+	srv.handle("Insert", func(msg map[string]interface{}) map[string]interface{} {
+		if attempts.Add(1) < 3 {
+			return map[string]interface{}{"type": "Error", "message": "busy"}
+		}
+		return documentsReply(map[string]interface{}{"id": "doc-1", "collection": "users"})
+	})
+
+	client := srv.connect(&Options{Retry: &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Retryable:      func(err error) bool { return err.Error() == "busy" },
+	}})
+
+	doc, err := client.Insert(context.Background(), "users", map[string]interface{}{"name": "Alice"})
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if doc == nil || doc.Id != "doc-1" {
+		t.Fatalf("Expected document doc-1, got %+v", doc)
+	}
+
+	msgs := srv.messages("Insert")
+	if len(msgs) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", len(msgs))
+	}
+	key := msgs[0]["idempotency_key"]
+	if key == nil || key == "" {
+		t.Fatal("Expected idempotency_key on insert")
+	}
+	for i, m := range msgs {
+		if m["idempotency_key"] != key {
+			t.Errorf("Attempt %d: expected idempotency_key %v, got %v", i, key, m["idempotency_key"])
+		}
+	}
+	if msgs[0]["id"] == msgs[1]["id"] {
+		t.Error("Expected each attempt to use a new request id")
+	}
+}
+
+func TestInsertWithoutRetryPolicyFailsOnce(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Insert", func(msg map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "Error", "message": "busy"}
+	})
+	client := srv.connect(nil)
+
+	if _, err := client.Insert(context.Background(), "users", map[string]interface{}{}); err == nil {
+		t.Fatal("Expected insert to fail")
+	}
+	if n := len(srv.messages("Insert")); n != 1 {
+		t.Errorf("Expected 1 attempt, got %d", n)
+	}
+}
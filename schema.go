@@ -0,0 +1,541 @@
+// SquirrelDB Go SDK - Schemas
+
+package squirreldb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Schema is a JSON Schema (draft 2020-12) restricted to the keywords below.
+// Other validation keywords are rejected by ParseSchema rather than silently
+// ignored; annotations such as title and description are accepted.
+type Schema struct {
+	Type SchemaType `json:"type,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	Items       *Schema `json:"items,omitempty"`
+	MinItems    *int    `json:"minItems,omitempty"`
+	MaxItems    *int    `json:"maxItems,omitempty"`
+	UniqueItems bool    `json:"uniqueItems,omitempty"`
+
+	Enum  []interface{}   `json:"enum,omitempty"`
+	Const json.RawMessage `json:"const,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	// Format is checked for date-time, date, email and uuid
+	Format string `json:"format,omitempty"`
+
+	AllOf []*Schema `json:"allOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// boolean is set for the boolean schemas true and false
+	boolean *bool
+}
+
+// SchemaType is the "type" keyword, a single type or a list of types
+type SchemaType []string
+
+// UnmarshalJSON accepts a string or an array of strings
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = SchemaType{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("schema: type must be a string or array of strings")
+	}
+	*t = many
+	return nil
+}
+
+// MarshalJSON writes a single type as a string
+func (t SchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+var schemaKeywords = map[string]bool{
+	"type": true, "properties": true, "required": true, "additionalProperties": true,
+	"items": true, "minItems": true, "maxItems": true, "uniqueItems": true,
+	"enum": true, "const": true,
+	"minimum": true, "maximum": true, "exclusiveMinimum": true, "exclusiveMaximum": true, "multipleOf": true,
+	"minLength": true, "maxLength": true, "pattern": true, "format": true,
+	"allOf": true, "anyOf": true, "oneOf": true, "not": true,
+	"title": true, "description": true,
+	// Annotations without effect on validation
+	"$schema": true, "$id": true, "$comment": true, "default": true, "examples": true,
+	"deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+var schemaTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true,
+	"number": true, "integer": true, "string": true,
+}
+
+// UnmarshalJSON decodes a schema object or boolean schema, rejecting
+// unsupported keywords
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("true")) || bytes.Equal(data, []byte("false")) {
+		b := data[0] == 't'
+		*s = Schema{boolean: &b}
+		return nil
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("schema: expected object or boolean")
+	}
+	for k := range keys {
+		if !schemaKeywords[k] {
+			return fmt.Errorf("schema: unsupported keyword %q", k)
+		}
+	}
+	type plain Schema
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*s = Schema(p)
+	return nil
+}
+
+// MarshalJSON encodes the schema, including boolean schemas
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.boolean != nil {
+		return json.Marshal(*s.boolean)
+	}
+	type plain Schema
+	return json.Marshal((*plain)(s))
+}
+
+// ParseSchema parses and checks a JSON Schema document
+func ParseSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := s.check(""); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// check verifies keyword values that JSON decoding cannot
+func (s *Schema) check(path string) error {
+	if s == nil || s.boolean != nil {
+		return nil
+	}
+	for _, t := range s.Type {
+		if !schemaTypes[t] {
+			return fmt.Errorf("schema %s: unknown type %q", displayPath(path), t)
+		}
+	}
+	if s.Pattern != "" {
+		if _, err := schemaPattern(s.Pattern); err != nil {
+			return fmt.Errorf("schema %s: %w", displayPath(path), err)
+		}
+	}
+	if s.MultipleOf != nil && *s.MultipleOf <= 0 {
+		return fmt.Errorf("schema %s: multipleOf must be positive", displayPath(path))
+	}
+	for name, p := range s.Properties {
+		if err := p.check(joinPath(path, name)); err != nil {
+			return err
+		}
+	}
+	subs := append(append(append([]*Schema{s.AdditionalProperties, s.Items, s.Not}, s.AllOf...), s.AnyOf...), s.OneOf...)
+	for _, sub := range subs {
+		if err := sub.check(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var schemaPatterns sync.Map // pattern -> *regexp.Regexp
+
+// schemaPattern compiles a pattern once; patterns use Go's RE2 syntax,
+// which covers the common subset of ECMA 262 regular expressions
+func schemaPattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := schemaPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	schemaPatterns.Store(pattern, re)
+	return re, nil
+}
+
+// SchemaViolation is a single reason a value does not match a schema
+type SchemaViolation struct {
+	// Path uses the dot syntax of Document.Get; empty for the root
+	Path    string
+	Message string
+}
+
+func (v SchemaViolation) String() string {
+	return displayPath(v.Path) + ": " + v.Message
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+// SchemaError lists every violation found while validating a value. It
+// matches ErrValidation.
+type SchemaError struct {
+	Collection string
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	prefix := "schema validation failed"
+	if e.Collection != "" {
+		prefix += " for " + e.Collection
+	}
+	return prefix + ": " + strings.Join(parts, "; ")
+}
+
+// Is matches ErrValidation
+func (e *SchemaError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Validate checks v against the schema, returning a *SchemaError listing
+// all violations. v is compared in its JSON form, so structs, maps and
+// documents data are all accepted.
+func (s *Schema) Validate(v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+	var violations []SchemaViolation
+	s.validate(value, "", &violations)
+	if len(violations) > 0 {
+		return &SchemaError{Violations: violations}
+	}
+	return nil
+}
+
+func (s *Schema) validate(v interface{}, path string, out *[]SchemaViolation) {
+	if s == nil {
+		return
+	}
+	fail := func(format string, args ...interface{}) {
+		*out = append(*out, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.boolean != nil {
+		if !*s.boolean {
+			fail("not allowed")
+		}
+		return
+	}
+
+	if len(s.Type) > 0 && !matchesType(s.Type, v) {
+		fail("expected %s, got %s", strings.Join(s.Type, " or "), jsonType(v))
+		// Other keywords would only repeat the type mismatch
+		return
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			found = found || jsonEqual(e, v)
+		}
+		if !found {
+			fail("must be one of %s", mustJSON(s.Enum))
+		}
+	}
+	if len(s.Const) > 0 {
+		var c interface{}
+		json.Unmarshal(s.Const, &c)
+		if !jsonEqual(c, v) {
+			fail("must be %s", s.Const)
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		s.validateObject(val, path, out)
+	case []interface{}:
+		s.validateArray(val, path, out)
+	case string:
+		s.validateString(val, fail)
+	case float64:
+		s.validateNumber(val, fail)
+	}
+
+	for _, sub := range s.AllOf {
+		sub.validate(v, path, out)
+	}
+	if len(s.AnyOf) > 0 && countMatches(s.AnyOf, v, path) == 0 {
+		fail("must match at least one schema in anyOf")
+	}
+	if len(s.OneOf) > 0 {
+		if n := countMatches(s.OneOf, v, path); n != 1 {
+			fail("must match exactly one schema in oneOf, matched %d", n)
+		}
+	}
+	if s.Not != nil && countMatches([]*Schema{s.Not}, v, path) == 1 {
+		fail("must not match schema in not")
+	}
+}
+
+func countMatches(schemas []*Schema, v interface{}, path string) int {
+	n := 0
+	for _, sub := range schemas {
+		var violations []SchemaViolation
+		sub.validate(v, path, &violations)
+		if len(violations) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Schema) validateObject(obj map[string]interface{}, path string, out *[]SchemaViolation) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			*out = append(*out, SchemaViolation{Path: joinPath(path, name), Message: "is required"})
+		}
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if prop, ok := s.Properties[k]; ok {
+			prop.validate(obj[k], joinPath(path, k), out)
+		} else if s.AdditionalProperties != nil {
+			if s.AdditionalProperties.boolean != nil && !*s.AdditionalProperties.boolean {
+				*out = append(*out, SchemaViolation{Path: joinPath(path, k), Message: "is not an allowed property"})
+				continue
+			}
+			s.AdditionalProperties.validate(obj[k], joinPath(path, k), out)
+		}
+	}
+}
+
+func (s *Schema) validateArray(arr []interface{}, path string, out *[]SchemaViolation) {
+	if s.MinItems != nil && len(arr) < *s.MinItems {
+		*out = append(*out, SchemaViolation{Path: path, Message: fmt.Sprintf("must have at least %d items", *s.MinItems)})
+	}
+	if s.MaxItems != nil && len(arr) > *s.MaxItems {
+		*out = append(*out, SchemaViolation{Path: path, Message: fmt.Sprintf("must have at most %d items", *s.MaxItems)})
+	}
+	if s.UniqueItems {
+	unique:
+		for i := range arr {
+			for j := 0; j < i; j++ {
+				if jsonEqual(arr[i], arr[j]) {
+					*out = append(*out, SchemaViolation{Path: path, Message: fmt.Sprintf("items %d and %d are equal", j, i)})
+					break unique
+				}
+			}
+		}
+	}
+	for i, item := range arr {
+		s.Items.validate(item, joinPath(path, fmt.Sprint(i)), out)
+	}
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func (s *Schema) validateString(str string, fail func(string, ...interface{})) {
+	n := utf8.RuneCountInString(str)
+	if s.MinLength != nil && n < *s.MinLength {
+		fail("must be at least %d characters", *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		fail("must be at most %d characters", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		if re, err := schemaPattern(s.Pattern); err == nil && !re.MatchString(str) {
+			fail("must match pattern %s", s.Pattern)
+		}
+	}
+	var ok bool
+	switch s.Format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, str)
+		ok = err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", str)
+		ok = err == nil
+	case "email":
+		addr, err := mail.ParseAddress(str)
+		ok = err == nil && addr.Address == str
+	case "uuid":
+		ok = uuidPattern.MatchString(str)
+	default:
+		ok = true
+	}
+	if !ok {
+		fail("must be a valid %s", s.Format)
+	}
+}
+
+func (s *Schema) validateNumber(f float64, fail func(string, ...interface{})) {
+	if s.Minimum != nil && f < *s.Minimum {
+		fail("must be >= %v", *s.Minimum)
+	}
+	if s.Maximum != nil && f > *s.Maximum {
+		fail("must be <= %v", *s.Maximum)
+	}
+	if s.ExclusiveMinimum != nil && f <= *s.ExclusiveMinimum {
+		fail("must be > %v", *s.ExclusiveMinimum)
+	}
+	if s.ExclusiveMaximum != nil && f >= *s.ExclusiveMaximum {
+		fail("must be < %v", *s.ExclusiveMaximum)
+	}
+	if s.MultipleOf != nil {
+		q := f / *s.MultipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("must be a multiple of %v", *s.MultipleOf)
+		}
+	}
+}
+
+func matchesType(types SchemaType, v interface{}) bool {
+	actual := jsonType(v)
+	for _, t := range types {
+		if t == actual || t == "number" && actual == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType names the JSON type of a decoded value; whole numbers are integers
+func jsonType(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		if val == math.Trunc(val) && !math.IsInf(val, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func jsonEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normalizeJSON(a), normalizeJSON(b))
+}
+
+func normalizeJSON(v interface{}) interface{} {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	json.Unmarshal(raw, &out)
+	return out
+}
+
+func mustJSON(v interface{}) string {
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
+
+// RegisterSchema makes the client validate data against schema before
+// sending it to the collection with Insert, Update, UpdateIf, Upsert,
+// Replace, InsertMany, UpdateMany and transactional writes. Update data is
+// validated as a whole document; operator updates made with UpdateWith are
+// not validated. A nil schema removes the registration.
+func (c *Client) RegisterSchema(collection string, schema *Schema) {
+	if schema == nil {
+		c.schemas.Delete(collection)
+		return
+	}
+	c.schemas.Store(collection, schema)
+}
+
+// validate checks data against the schema registered for the collection
+func (c *Client) validate(collection string, data map[string]interface{}) error {
+	v, ok := c.schemas.Load(collection)
+	if !ok {
+		return nil
+	}
+	err := v.(*Schema).Validate(data)
+	if schemaErr, ok := err.(*SchemaError); ok {
+		schemaErr.Collection = collection
+	}
+	return err
+}
+
+// validateMessage checks the data of a write message, if it carries any
+func (c *Client) validateMessage(msg map[string]interface{}) error {
+	data, ok := msg["data"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	collection, _ := msg["collection"].(string)
+	return c.validate(collection, data)
+}
+
+// PushSchema registers the schema with the server, which then rejects
+// invalid writes from every client with an error matching ErrValidation.
+// A nil schema removes it.
+func (c *Client) PushSchema(ctx context.Context, collection string, schema *Schema) error {
+	_, err := c.admin(ctx, map[string]interface{}{
+		"type":       "SetSchema",
+		"collection": collection,
+		"schema":     schema,
+	})
+	return err
+}
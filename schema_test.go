@@ -0,0 +1,226 @@
+// SquirrelDB Go SDK - Schema Tests
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const userSchema = `{
+	"title": "user",
+	"type": "object",
+	"required": ["name", "email"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"email": {"type": "string", "format": "email"},
+		"age": {"type": "integer", "minimum": 0},
+		"role": {"enum": ["admin", "member"]},
+		"tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}, "uniqueItems": true},
+		"address": {
+			"type": "object",
+			"required": ["city"],
+			"properties": {"city": {"type": "string"}, "zip": {"type": ["string", "null"]}}
+		},
+		"created_at": {"type": "string", "format": "date-time"}
+	}
+}`
+
+func mustParseSchema(t *testing.T, src string) *Schema {
+	t.Helper()
+	s, err := ParseSchema([]byte(src))
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+	return s
+}
+
+func violationPaths(err error) []string {
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		return nil
+	}
+	var paths []string
+	for _, v := range schemaErr.Violations {
+		paths = append(paths, displayPath(v.Path))
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestParseSchemaRejectsInvalidSchemas(t *testing.T) {
+	tests := []string{
+		`{"type": "object", "$ref": "#/defs/user"}`,
+		`{"type": "text"}`,
+		`{"properties": {"name": {"type": "string", "pattern": "(["}}}`,
+		`{"type": "number", "multipleOf": 0}`,
+		`[]`,
+	}
+	for _, src := range tests {
+		if _, err := ParseSchema([]byte(src)); err == nil {
+			t.Errorf("Expected error parsing %s", src)
+		}
+	}
+}
+
+func TestSchemaRoundTrip(t *testing.T) {
+	s := mustParseSchema(t, userSchema)
+	raw, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	again := mustParseSchema(t, string(raw))
+	if !strings.Contains(string(raw), `"additionalProperties":false`) {
+		t.Errorf("Expected boolean schema to survive, got %s", raw)
+	}
+	if err := again.Validate(map[string]interface{}{"name": "Ann", "email": "ann@example.com", "extra": 1}); err == nil {
+		t.Error("Expected round-tripped schema to reject extra properties")
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	s := mustParseSchema(t, userSchema)
+
+	valid := map[string]interface{}{
+		"name":       "Ann",
+		"email":      "ann@example.com",
+		"age":        30,
+		"role":       "admin",
+		"tags":       []string{"go", "db"},
+		"address":    map[string]interface{}{"city": "Oslo", "zip": nil},
+		"created_at": "2024-05-01T10:00:00Z",
+	}
+	if err := s.Validate(valid); err != nil {
+		t.Errorf("Expected valid document, got %v", err)
+	}
+
+	invalid := map[string]interface{}{
+		"name":       "",
+		"age":        1.5,
+		"role":       "owner",
+		"tags":       []string{"go", "Go", "go"},
+		"address":    map[string]interface{}{"zip": 1234},
+		"created_at": "yesterday",
+		"nickname":   "annie",
+	}
+	err := s.Validate(invalid)
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected error matching ErrValidation, got %v", err)
+	}
+	want := []string{"address.city", "address.zip", "age", "created_at", "email", "name", "nickname", "role", "tags", "tags.1"}
+	if got := violationPaths(err); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected violations at %v, got %v\n%v", want, got, err)
+	}
+}
+
+func TestSchemaValidateStruct(t *testing.T) {
+	s := mustParseSchema(t, userSchema)
+	type user struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	if err := s.Validate(user{Name: "Ann", Email: "ann@example.com"}); err != nil {
+		t.Errorf("Expected struct to validate, got %v", err)
+	}
+	if err := s.Validate(user{Name: "Ann", Email: "not an email"}); err == nil {
+		t.Error("Expected invalid email in struct to fail")
+	}
+}
+
+func TestSchemaCombinators(t *testing.T) {
+	s := mustParseSchema(t, `{
+		"properties": {
+			"id": {"anyOf": [{"type": "integer"}, {"type": "string", "format": "uuid"}]},
+			"price": {"oneOf": [{"type": "number", "multipleOf": 5}, {"type": "number", "multipleOf": 3}]},
+			"status": {"not": {"const": "deleted"}},
+			"code": {"allOf": [{"type": "string"}, {"maxLength": 4}]}
+		}
+	}`)
+
+	ok := map[string]interface{}{"id": "123e4567-e89b-12d3-a456-426614174000", "price": 10, "status": "active", "code": "ab"}
+	if err := s.Validate(ok); err != nil {
+		t.Errorf("Expected valid document, got %v", err)
+	}
+
+	bad := map[string]interface{}{"id": "abc", "price": 15, "status": "deleted", "code": "abcdef"}
+	want := []string{"code", "id", "price", "status"}
+	if got := violationPaths(s.Validate(bad)); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected violations at %v, got %v", want, got)
+	}
+}
+
+func TestRegisterSchemaBlocksInvalidWrites(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Insert", func(msg map[string]interface{}) map[string]interface{} {
+		return documentsReply(map[string]interface{}{"id": "u1", "collection": "users", "data": msg["data"]})
+	})
+	srv.handle("InsertMany", func(msg map[string]interface{}) map[string]interface{} {
+		docs, _ := msg["documents"].([]interface{})
+		results := make([]interface{}, len(docs))
+		for i, d := range docs {
+			results[i] = map[string]interface{}{"document": map[string]interface{}{"id": "u", "collection": "users", "data": d}}
+		}
+		return map[string]interface{}{"type": "Result", "results": results}
+	})
+	client := srv.connect(nil)
+	client.RegisterSchema("users", mustParseSchema(t, userSchema))
+	ctx := context.Background()
+
+	_, err := client.Insert(ctx, "users", map[string]interface{}{"name": "Ann"})
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || schemaErr.Collection != "users" {
+		t.Fatalf("Expected *SchemaError for users, got %v", err)
+	}
+	if n := len(srv.messages("Insert")); n != 0 {
+		t.Errorf("Expected invalid insert not to be sent, got %d messages", n)
+	}
+
+	if _, err := client.Insert(ctx, "users", map[string]interface{}{"name": "Ann", "email": "ann@example.com"}); err != nil {
+		t.Errorf("Expected valid insert to succeed, got %v", err)
+	}
+	if _, err := client.Insert(ctx, "other", map[string]interface{}{"anything": true}); err != nil {
+		t.Errorf("Expected collection without schema to accept anything, got %v", err)
+	}
+
+	res, err := client.InsertMany(ctx, "users", []map[string]interface{}{
+		{"name": "Bob", "email": "bob@example.com"},
+		{"name": "Eve"},
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected bulk error matching ErrValidation, got %v", err)
+	}
+	if len(res.Succeeded()) != 1 || res.Items[1].Err == nil {
+		t.Errorf("Expected only the valid item to succeed, got %+v", res.Items)
+	}
+	sent := srv.messages("InsertMany")
+	if len(sent) != 1 || len(sent[0]["documents"].([]interface{})) != 1 {
+		t.Errorf("Expected only the valid item to be sent, got %v", sent)
+	}
+
+	client.RegisterSchema("users", nil)
+	if _, err := client.Insert(ctx, "users", map[string]interface{}{"name": "Ann"}); err != nil {
+		t.Errorf("Expected insert to succeed after removing schema, got %v", err)
+	}
+}
+
+func TestPushSchema(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("SetSchema", func(msg map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "Result"}
+	})
+	client := srv.connect(nil)
+
+	if err := client.PushSchema(context.Background(), "users", mustParseSchema(t, userSchema)); err != nil {
+		t.Fatalf("PushSchema failed: %v", err)
+	}
+	msg := srv.messages("SetSchema")[0]
+	schema, _ := msg["schema"].(map[string]interface{})
+	if msg["collection"] != "users" || schema["additionalProperties"] != false || schema["type"] != "object" {
+		t.Errorf("Unexpected SetSchema message: %v", msg)
+	}
+}
@@ -0,0 +1,496 @@
+// SquirrelDB Go SDK - Schema Generation
+
+package squirreldb
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schemas and indexes can be generated from the struct types mapped to
+// documents, so they stay in sync with the Go code:
+//
+//	type User struct {
+//		ID       string    `sqrl:"id"`
+//		Email    string    `sqrl:"email" validate:"required,email" index:"unique"`
+//		Name     string    `sqrl:"name" validate:"min=1,max=100"`
+//		Role     string    `sqrl:"role" validate:"oneof=admin member"`
+//		Nickname *string   `sqrl:"nickname"`
+//		Tags     []string  `sqrl:"tags" validate:"unique"`
+//		OrgID    string    `sqrl:"org_id" index:"name=org_name"`
+//		Expires  time.Time `sqrl:"expires" index:"ttl=720h"`
+//	}
+//
+// Field names follow the sqrl and json tags as described for the struct
+// mapping, and the id and timestamp fields are left out of the schema since
+// they are not stored in document data. Fields that are always encoded are
+// required; pointer and omitempty fields are optional, and pointers, slices
+// and maps also accept null.
+//
+// The validate tag uses the rule names of the common Go validator packages.
+// Supported rules are required, min, max, len, gt, gte, lt, lte, oneof,
+// email, uuid, unique, alpha and alphanum; other rules are ignored. On a
+// string, required also rejects the empty string. With omitempty, a string,
+// number or bool field may hold its zero value regardless of the other rules.
+//
+// The index tag indexes a field, optionally with the options asc, desc,
+// unique, ttl=<duration> and name=<name>. Fields sharing a name form a
+// compound index, with keys in field order.
+
+// SchemaFor generates the collection schema for the struct type of v, which
+// may be a struct, a pointer to one or a reflect.Type
+func SchemaFor(v interface{}) (*Schema, error) {
+	t, err := mappedStruct(v)
+	if err != nil {
+		return nil, err
+	}
+	g := &schemaGen{visiting: make(map[reflect.Type]bool)}
+	return g.object(t, "", true)
+}
+
+// IndexesFor returns the indexes declared with index tags on the struct
+// type of v, for use with EnsureIndexes
+func IndexesFor(collection string, v interface{}) ([]IndexSpec, error) {
+	t, err := mappedStruct(v)
+	if err != nil {
+		return nil, err
+	}
+	g := &indexGen{collection: collection, named: make(map[string]int), visiting: make(map[reflect.Type]bool)}
+	if err := g.collect(t, "", true); err != nil {
+		return nil, err
+	}
+	for _, spec := range g.specs {
+		if err := spec.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return g.specs, nil
+}
+
+func mappedStruct(v interface{}) (reflect.Type, error) {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || t == timeType {
+		return nil, fmt.Errorf("sqrl: cannot generate schema for %v, need a struct type", t)
+	}
+	return t, nil
+}
+
+// mappedField returns the struct field of f and whether it is reached
+// through an embedded pointer, which leaves it out when the pointer is nil
+func mappedField(t reflect.Type, f fieldInfo) (reflect.StructField, bool) {
+	viaPointer := false
+	for _, x := range f.index[:len(f.index)-1] {
+		t = t.Field(x).Type
+		if t.Kind() == reflect.Ptr {
+			viaPointer = true
+			t = t.Elem()
+		}
+	}
+	return t.Field(f.index[len(f.index)-1]), viaPointer
+}
+
+// isOpaque reports whether values of t encode themselves, so their
+// structure is unknown
+func isOpaque(t reflect.Type) bool {
+	return t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface && t != timeType &&
+		(t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType))
+}
+
+type schemaGen struct {
+	visiting map[reflect.Type]bool
+}
+
+func (g *schemaGen) object(t reflect.Type, path string, top bool) (*Schema, error) {
+	if g.visiting[t] {
+		return nil, fmt.Errorf("sqrl: field %s: recursive type %s", displayPath(path), t)
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	closed := false
+	s := &Schema{
+		Type:                 SchemaType{"object"},
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: &Schema{boolean: &closed},
+	}
+	for _, f := range structFields(t) {
+		if top && f.role != roleData {
+			continue
+		}
+		sf, viaPointer := mappedField(t, f)
+		fieldPath := joinPath(path, f.name)
+		rules := parseRules(sf.Tag.Get("validate"))
+		fs, err := g.field(sf.Type, fieldPath, rules)
+		if err != nil {
+			return nil, err
+		}
+		s.Properties[f.name] = fs
+		if rules.required || (sf.Type.Kind() != reflect.Ptr && !f.omitEmpty && !viaPointer) {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	return s, nil
+}
+
+// field generates the schema of a value of type t with validation rules
+func (g *schemaGen) field(t reflect.Type, path string, rules fieldRules) (*Schema, error) {
+	base := t
+	if base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+	s, err := g.value(base, path)
+	if err != nil {
+		return nil, err
+	}
+
+	constraints := s
+	zero, hasZero := zeroValue(base)
+	if rules.omitEmpty && hasZero {
+		constraints = &Schema{}
+	}
+	for _, r := range rules.rules {
+		if err := applyRule(constraints, base, r); err != nil {
+			return nil, fmt.Errorf("sqrl: field %s: %w", path, err)
+		}
+	}
+	if constraints != s && mustJSON(constraints) != "{}" {
+		s.AnyOf = []*Schema{{Const: zero}, constraints}
+	}
+
+	nilable := t.Kind() == reflect.Ptr ||
+		(!isOpaque(base) && (base.Kind() == reflect.Slice || base.Kind() == reflect.Map))
+	if nilable && !rules.required {
+		s = nullable(s)
+	}
+	return s, nil
+}
+
+// value generates the schema of a non-nil value of type t
+func (g *schemaGen) value(t reflect.Type, path string) (*Schema, error) {
+	if t == timeType {
+		return &Schema{Type: SchemaType{"string"}, Format: "date-time"}, nil
+	}
+	if isOpaque(t) {
+		return &Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: SchemaType{"boolean"}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: SchemaType{"integer"}}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		min := 0.0
+		return &Schema{Type: SchemaType{"integer"}, Minimum: &min}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: SchemaType{"number"}}, nil
+	case reflect.String:
+		return &Schema{Type: SchemaType{"string"}}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Ptr:
+		return g.field(t, path, fieldRules{})
+	case reflect.Struct:
+		return g.object(t, path, false)
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("sqrl: field %s: map keys must be strings", path)
+		}
+		values, err := g.field(t.Elem(), path, fieldRules{})
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: SchemaType{"object"}, AdditionalProperties: values}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: SchemaType{"string"}}, nil
+		}
+		items, err := g.field(t.Elem(), path, fieldRules{})
+		if err != nil {
+			return nil, err
+		}
+		s := &Schema{Type: SchemaType{"array"}, Items: items}
+		if t.Kind() == reflect.Array {
+			n := t.Len()
+			s.MinItems, s.MaxItems = &n, &n
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("sqrl: field %s: unsupported type %s", path, t)
+}
+
+// nullable makes s also accept null
+func nullable(s *Schema) *Schema {
+	if len(s.Type) > 0 && !matchesType(s.Type, nil) {
+		s.Type = append(s.Type, "null")
+	}
+	if len(s.Enum) > 0 {
+		s.Enum = append(s.Enum, nil)
+	}
+	if len(s.AnyOf) > 0 {
+		s.AnyOf = append(s.AnyOf, &Schema{Type: SchemaType{"null"}})
+	}
+	return s
+}
+
+// zeroValue returns the encoded zero value of a string, number or bool type
+func zeroValue(t reflect.Type) ([]byte, bool) {
+	switch t.Kind() {
+	case reflect.String:
+		return []byte(`""`), true
+	case reflect.Bool:
+		return []byte(`false`), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return []byte(`0`), true
+	}
+	return nil, false
+}
+
+type fieldRule struct {
+	name, param string
+}
+
+type fieldRules struct {
+	required  bool
+	omitEmpty bool
+	rules     []fieldRule
+}
+
+func parseRules(tag string) fieldRules {
+	var rules fieldRules
+	if tag == "" {
+		return rules
+	}
+	for _, part := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch name {
+		case "required":
+			rules.required = true
+		case "omitempty":
+			rules.omitEmpty = true
+		case "dive":
+			// Rules after dive apply to elements, which are not supported
+			return rules
+		}
+		rules.rules = append(rules.rules, fieldRule{name, param})
+	}
+	return rules
+}
+
+// applyRule adds the constraint of a validate rule to s for a value of type t
+func applyRule(s *Schema, t reflect.Type, r fieldRule) error {
+	kind := t.Kind()
+	isString := kind == reflect.String
+	isNumber := false
+	if _, ok := zeroValue(t); ok && !isString && kind != reflect.Bool {
+		isNumber = true
+	}
+	isList := kind == reflect.Slice || kind == reflect.Array
+	if t == timeType || isOpaque(t) {
+		return nil
+	}
+
+	size := func() (int, error) {
+		n, err := strconv.Atoi(r.param)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid validate rule %s=%s", r.name, r.param)
+		}
+		return n, nil
+	}
+	number := func() (*float64, error) {
+		f, err := strconv.ParseFloat(r.param, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validate rule %s=%s", r.name, r.param)
+		}
+		return &f, nil
+	}
+
+	switch r.name {
+	case "required":
+		if isString {
+			n := 1
+			s.MinLength = &n
+		}
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+		if isNumber {
+			f, err := number()
+			if err != nil {
+				return err
+			}
+			switch r.name {
+			case "min", "gte":
+				s.Minimum = f
+			case "max", "lte":
+				s.Maximum = f
+			case "len":
+				s.Minimum, s.Maximum = f, f
+			case "gt":
+				s.ExclusiveMinimum = f
+			case "lt":
+				s.ExclusiveMaximum = f
+			}
+			return nil
+		}
+		if !isString && !isList {
+			return nil
+		}
+		n, err := size()
+		if err != nil {
+			return err
+		}
+		min, max := &n, &n
+		switch r.name {
+		case "min", "gte":
+			max = nil
+		case "max", "lte":
+			min = nil
+		case "gt":
+			m := n + 1
+			min, max = &m, nil
+		case "lt":
+			m := n - 1
+			min, max = nil, &m
+		}
+		if isString {
+			if min != nil {
+				s.MinLength = min
+			}
+			if max != nil {
+				s.MaxLength = max
+			}
+		} else {
+			if min != nil {
+				s.MinItems = min
+			}
+			if max != nil {
+				s.MaxItems = max
+			}
+		}
+	case "oneof":
+		for _, opt := range strings.Fields(r.param) {
+			if isNumber {
+				f, err := strconv.ParseFloat(opt, 64)
+				if err != nil {
+					return fmt.Errorf("invalid oneof value %q", opt)
+				}
+				s.Enum = append(s.Enum, f)
+			} else if isString {
+				s.Enum = append(s.Enum, opt)
+			}
+		}
+	case "email":
+		s.Format = "email"
+	case "uuid", "uuid4":
+		s.Format = "uuid"
+	case "datetime":
+		switch r.param {
+		case "2006-01-02":
+			s.Format = "date"
+		case time.RFC3339, time.RFC3339Nano:
+			s.Format = "date-time"
+		}
+	case "unique":
+		if isList {
+			s.UniqueItems = true
+		}
+	case "alpha":
+		s.Pattern = "^[a-zA-Z]+$"
+	case "alphanum":
+		s.Pattern = "^[a-zA-Z0-9]+$"
+	}
+	return nil
+}
+
+type indexGen struct {
+	collection string
+	specs      []IndexSpec
+	named      map[string]int // index name -> position in specs
+	visiting   map[reflect.Type]bool
+}
+
+func (g *indexGen) collect(t reflect.Type, path string, top bool) error {
+	if g.visiting[t] {
+		return nil
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	for _, f := range structFields(t) {
+		sf, _ := mappedField(t, f)
+		fieldPath := joinPath(path, f.name)
+		if tag, ok := sf.Tag.Lookup("index"); ok {
+			if top && f.role == roleID {
+				return fmt.Errorf("sqrl: field %s: the document id is always indexed", fieldPath)
+			}
+			if err := g.add(fieldPath, tag); err != nil {
+				return err
+			}
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType && !isOpaque(ft) {
+			if err := g.collect(ft, fieldPath, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// add applies the index tag of the field at path
+func (g *indexGen) add(path, tag string) error {
+	spec := IndexSpec{Collection: g.collection}
+	key := SortSpec{Field: path, Direction: SortAsc}
+	for _, opt := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch name {
+		case "":
+		case "asc":
+			key.Direction = SortAsc
+		case "desc":
+			key.Direction = SortDesc
+		case "unique":
+			spec.Unique = true
+		case "name":
+			spec.Name = param
+		case "ttl":
+			d, err := time.ParseDuration(param)
+			if err != nil || d < time.Second {
+				return fmt.Errorf("sqrl: field %s: invalid index ttl %q", path, param)
+			}
+			spec.TTL = d
+		default:
+			return fmt.Errorf("sqrl: field %s: unknown index option %q", path, name)
+		}
+	}
+
+	if spec.Name != "" {
+		if i, ok := g.named[spec.Name]; ok {
+			existing := &g.specs[i]
+			existing.Keys = append(existing.Keys, key)
+			existing.Unique = existing.Unique || spec.Unique
+			if spec.TTL > 0 {
+				existing.TTL = spec.TTL
+			}
+			return nil
+		}
+		g.named[spec.Name] = len(g.specs)
+	}
+	spec.Keys = []SortSpec{key}
+	g.specs = append(g.specs, spec)
+	return nil
+}
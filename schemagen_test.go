@@ -0,0 +1,183 @@
+// SquirrelDB Go SDK - Schema Generation Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type genAddress struct {
+	City string  `json:"city" validate:"required"`
+	Zip  *string `json:"zip" index:""`
+}
+
+type genAudit struct {
+	Source string `sqrl:"source"`
+}
+
+type genUser struct {
+	ID        string            `sqrl:"id"`
+	Email     string            `sqrl:"email" validate:"required,email" index:"unique"`
+	Name      string            `sqrl:"name" validate:"min=1,max=20"`
+	Age       uint8             `sqrl:"age" validate:"lte=130"`
+	Role      string            `sqrl:"role" validate:"oneof=admin member"`
+	Nickname  *string           `sqrl:"nickname" validate:"omitempty,alpha"`
+	Bio       string            `sqrl:"bio,omitempty"`
+	Code      string            `sqrl:"code" validate:"omitempty,len=4"`
+	Tags      []string          `sqrl:"tags" validate:"unique,max=3"`
+	Address   genAddress        `sqrl:"address"`
+	Labels    map[string]string `sqrl:"labels"`
+	Raw       interface{}       `sqrl:"raw"`
+	OrgID     string            `sqrl:"org_id" index:"name=org_created"`
+	Internal  string            `sqrl:"-"`
+	CreatedAt time.Time         `sqrl:"created_at" index:"name=org_created,desc"`
+	Expires   time.Time         `sqrl:"expires" index:"ttl=24h"`
+	*genAudit
+}
+
+func TestSchemaForStruct(t *testing.T) {
+	s, err := SchemaFor(&genUser{})
+	if err != nil {
+		t.Fatalf("SchemaFor failed: %v", err)
+	}
+
+	var props []string
+	for name := range s.Properties {
+		props = append(props, name)
+	}
+	sort.Strings(props)
+	wantProps := "address,age,bio,code,email,expires,labels,name,nickname,org_id,raw,role,source,tags"
+	if strings.Join(props, ",") != wantProps {
+		t.Errorf("Expected properties %s, got %v", wantProps, props)
+	}
+	wantRequired := "email,name,age,role,code,tags,address,labels,raw,org_id,expires"
+	if strings.Join(s.Required, ",") != wantRequired {
+		t.Errorf("Expected required %s, got %v", wantRequired, s.Required)
+	}
+	if got := s.Properties["nickname"].Type; !reflect.DeepEqual(got, SchemaType{"string", "null"}) {
+		t.Errorf("Expected pointer to be nullable, got %v", got)
+	}
+	if got := s.Properties["expires"].Format; got != "date-time" {
+		t.Errorf("Expected date-time format for time.Time, got %q", got)
+	}
+
+	valid := genUser{
+		Email:   "ann@example.com",
+		Name:    "Ann",
+		Age:     30,
+		Role:    "admin",
+		Tags:    []string{"a", "b"},
+		Address: genAddress{City: "Oslo"},
+		Raw:     []int{1, 2},
+		Expires: time.Now(),
+	}
+	data, err := ToData(valid)
+	if err != nil {
+		t.Fatalf("ToData failed: %v", err)
+	}
+	if err := s.Validate(data); err != nil {
+		t.Errorf("Expected mapped struct to validate, got %v", err)
+	}
+
+	nick := "ann1"
+	invalid := valid
+	invalid.Email = "ann"
+	invalid.Name = ""
+	invalid.Age = 200
+	invalid.Role = "owner"
+	invalid.Nickname = &nick
+	invalid.Code = "abc"
+	invalid.Tags = []string{"a", "a"}
+	invalid.Address.City = ""
+	invalid.genAudit = &genAudit{Source: "import"}
+	data, _ = ToData(invalid)
+	data["extra"] = true
+	want := []string{"address.city", "age", "code", "email", "extra", "name", "nickname", "role", "tags"}
+	if got := violationPaths(s.Validate(data)); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected violations at %v, got %v", want, got)
+	}
+}
+
+func TestSchemaForErrors(t *testing.T) {
+	type node struct {
+		Children []node `json:"children"`
+	}
+	type badRule struct {
+		Name string `json:"name" validate:"max=many"`
+	}
+	type badKey struct {
+		Counts map[int]int `json:"counts"`
+	}
+	for _, v := range []interface{}{"not a struct", node{}, badRule{}, badKey{}} {
+		if _, err := SchemaFor(v); err == nil {
+			t.Errorf("Expected error generating schema for %T", v)
+		}
+	}
+	if _, err := SchemaFor(reflect.TypeOf(genAddress{})); err != nil {
+		t.Errorf("Expected reflect.Type to be accepted, got %v", err)
+	}
+}
+
+func TestIndexesFor(t *testing.T) {
+	specs, err := IndexesFor("users", genUser{})
+	if err != nil {
+		t.Fatalf("IndexesFor failed: %v", err)
+	}
+	want := []IndexSpec{
+		{Collection: "users", Keys: []SortSpec{{Field: "email", Direction: SortAsc}}, Unique: true},
+		{Collection: "users", Keys: []SortSpec{{Field: "address.zip", Direction: SortAsc}}},
+		{Collection: "users", Name: "org_created", Keys: []SortSpec{{Field: "org_id", Direction: SortAsc}, {Field: "created_at", Direction: SortDesc}}},
+		{Collection: "users", Keys: []SortSpec{{Field: "expires", Direction: SortAsc}}, TTL: 24 * time.Hour},
+	}
+	if !reflect.DeepEqual(specs, want) {
+		t.Errorf("Expected %+v, got %+v", want, specs)
+	}
+
+	type badTTL struct {
+		A string `json:"a" index:"name=ab,ttl=1h"`
+		B string `json:"b" index:"name=ab"`
+	}
+	type badOption struct {
+		A string `json:"a" index:"sparse"`
+	}
+	type indexedID struct {
+		ID string `json:"id" index:"unique"`
+	}
+	for _, v := range []interface{}{badTTL{}, badOption{}, indexedID{}} {
+		if _, err := IndexesFor("things", v); err == nil {
+			t.Errorf("Expected error for %T", v)
+		}
+	}
+}
+
+func TestGeneratedSchemaAndIndexesApply(t *testing.T) {
+	srv := newFakeServer(t)
+	serveIndexes(srv)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	schema, err := SchemaFor(genUser{})
+	if err != nil {
+		t.Fatalf("SchemaFor failed: %v", err)
+	}
+	client.RegisterSchema("users", schema)
+	users := NewCollection[genUser](client, "users")
+	if _, err := users.Insert(ctx, genUser{Email: "bad"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected generated schema to reject the insert, got %v", err)
+	}
+
+	specs, _ := IndexesFor("users", genUser{})
+	changes, err := client.EnsureIndexes(ctx, specs)
+	if err != nil {
+		t.Fatalf("EnsureIndexes failed: %v", err)
+	}
+	if len(changes.Created) != len(specs) {
+		t.Errorf("Expected %d indexes created, got %+v", len(specs), changes)
+	}
+}
@@ -0,0 +1,704 @@
+// SquirrelDB Go SDK - Fake Server for Tests
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeHandler answers a single request; a nil reply sends nothing back
+type fakeHandler func(msg map[string]interface{}) map[string]interface{}
+
+// fakeContextHandler is a fakeHandler whose ctx is cancelled when the client
+// sends a Cancel message for the request
+type fakeContextHandler func(ctx context.Context, msg map[string]interface{}) map[string]interface{}
+
+// fakeServer is a scriptable in-process SquirrelDB WebSocket server
+type fakeServer struct {
+	t        *testing.T
+	srv      *httptest.Server
+	mu       sync.Mutex
+	handlers map[string]fakeContextHandler
+	inflight map[interface{}]context.CancelFunc
+	received []map[string]interface{}
+	conns    []*websocket.Conn
+	// writeMu serializes writes to the connections
+	writeMu sync.Mutex
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	s := &fakeServer{
+		t:        t,
+		handlers: map[string]fakeContextHandler{},
+		inflight: map[interface{}]context.CancelFunc{},
+	}
+	upgrader := websocket.Upgrader{}
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		s.serve(conn)
+	}))
+	t.Cleanup(s.close)
+	return s
+}
+
+func (s *fakeServer) serve(conn *websocket.Conn) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		msgType, _ := msg["type"].(string)
+
+		s.mu.Lock()
+		s.received = append(s.received, msg)
+		h := s.handlers[msgType]
+		if msgType == "Cancel" {
+			if cancel, ok := s.inflight[msg["request_id"]]; ok {
+				cancel()
+			}
+			s.mu.Unlock()
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		s.inflight[msg["id"]] = cancel
+		s.mu.Unlock()
+
+		go func() {
+			defer func() {
+				s.mu.Lock()
+				delete(s.inflight, msg["id"])
+				s.mu.Unlock()
+				cancel()
+			}()
+
+			var reply map[string]interface{}
+			if h != nil {
+				reply = h(ctx, msg)
+			} else if msgType != "Ping" {
+				reply = map[string]interface{}{"type": "Error", "message": "unknown message type " + msgType}
+			}
+			if ctx.Err() != nil {
+				reply = map[string]interface{}{"type": "Error", "code": CodeCancelled, "message": "request cancelled"}
+			}
+			if reply == nil {
+				return
+			}
+			if _, ok := reply["id"]; !ok {
+				reply["id"] = msg["id"]
+			}
+			out, _ := json.Marshal(reply)
+			s.writeMu.Lock()
+			conn.WriteMessage(websocket.TextMessage, out)
+			s.writeMu.Unlock()
+		}()
+	}
+}
+
+// handle registers the handler for a message type
+func (s *fakeServer) handle(msgType string, h fakeHandler) {
+	s.handleContext(msgType, func(ctx context.Context, msg map[string]interface{}) map[string]interface{} {
+		return h(msg)
+	})
+}
+
+// handleContext registers a cancellation-aware handler for a message type
+func (s *fakeServer) handleContext(msgType string, h fakeContextHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[msgType] = h
+}
+
+// push sends an unsolicited message, such as a Change, to every connection
+func (s *fakeServer) push(msg map[string]interface{}) {
+	out, _ := json.Marshal(msg)
+	s.mu.Lock()
+	conns := append([]*websocket.Conn(nil), s.conns...)
+	s.mu.Unlock()
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	for _, conn := range conns {
+		conn.WriteMessage(websocket.TextMessage, out)
+	}
+}
+
+// messages returns the requests received with the given type
+func (s *fakeServer) messages(msgType string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []map[string]interface{}
+	for _, m := range s.received {
+		if m["type"] == msgType {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// options returns client options pointing at the fake server
+func (s *fakeServer) options() *Options {
+	host, portStr, _ := net.SplitHostPort(s.srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	return &Options{Host: host, Port: port}
+}
+
+// connect opens a client against the fake server using opts as a template
+func (s *fakeServer) connect(opts *Options) *Client {
+	s.t.Helper()
+	base := s.options()
+	if opts != nil {
+		opts.Host, opts.Port = base.Host, base.Port
+		base = opts
+	}
+	client, err := Connect(context.Background(), base)
+	if err != nil {
+		s.t.Fatalf("Failed to connect to fake server: %v", err)
+	}
+	s.t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func (s *fakeServer) close() {
+	s.mu.Lock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	s.srv.Close()
+}
+
+// documentsReply builds a Result reply carrying the given documents
+func documentsReply(docs ...map[string]interface{}) map[string]interface{} {
+	if docs == nil {
+		docs = []map[string]interface{}{}
+	}
+	return map[string]interface{}{"type": "Result", "documents": docs}
+}
+
+// serveDocuments answers structured Query requests from an in-memory list of
+// documents, supporting simple comparison filters, sort, skip and limit
+func (s *fakeServer) serveDocuments(docs ...Document) {
+	s.handle("Query", func(msg map[string]interface{}) map[string]interface{} {
+		q, _ := msg["structured_query"].(map[string]interface{})
+		return documentsReply(fakeQuery(docs, q)...)
+	})
+	s.handle("Aggregate", func(msg map[string]interface{}) map[string]interface{} {
+		agg, _ := msg["aggregate"].(map[string]interface{})
+		return map[string]interface{}{"type": "Result", "rows": fakeAggregate(docs, agg)}
+	})
+}
+
+// fakeAggregate evaluates a structured aggregation against docs, sorting
+// and limiting the output rows
+func fakeAggregate(docs []Document, agg map[string]interface{}) []map[string]interface{} {
+	q, _ := agg["query"].(map[string]interface{})
+	input := fakeQuery(docs, map[string]interface{}{"table": q["table"], "filter": q["filter"]})
+	groupBy, _ := agg["group_by"].([]interface{})
+	fields, _ := agg["fields"].([]interface{})
+
+	var keys []string
+	groups := map[string][]map[string]interface{}{}
+	for _, doc := range input {
+		raw, _ := json.Marshal(fakeGroup(doc, groupBy))
+		key := string(raw)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], doc)
+	}
+	if len(groupBy) == 0 && len(keys) == 0 {
+		keys = append(keys, "{}")
+	}
+
+	var rows []map[string]interface{}
+	for _, key := range keys {
+		members := groups[key]
+		values := map[string]interface{}{}
+		for _, f := range fields {
+			spec := f.(map[string]interface{})
+			field, _ := spec["field"].(string)
+			var result interface{}
+			seen := map[string]bool{}
+			sum, n := 0.0, 0
+			for _, doc := range members {
+				v := fakeField(doc, field)
+				switch spec["op"] {
+				case "count":
+					n++
+					continue
+				case "count_distinct":
+					raw, _ := json.Marshal(v)
+					seen[string(raw)] = true
+					continue
+				}
+				if v == nil {
+					continue
+				}
+				f, _ := toFloat(v)
+				sum, n = sum+f, n+1
+				if result == nil ||
+					spec["op"] == "min" && fakeCompare(v, result) < 0 ||
+					spec["op"] == "max" && fakeCompare(v, result) > 0 {
+					result = v
+				}
+			}
+			switch spec["op"] {
+			case "count":
+				result = n
+			case "count_distinct":
+				result = len(seen)
+			case "sum":
+				result = sum
+			case "avg":
+				result = nil
+				if n > 0 {
+					result = sum / float64(n)
+				}
+			}
+			values[spec["name"].(string)] = result
+		}
+		var first map[string]interface{}
+		if len(members) > 0 {
+			first = members[0]
+		}
+		rows = append(rows, map[string]interface{}{"group": fakeGroup(first, groupBy), "values": values})
+	}
+
+	flat := make([]Document, len(rows))
+	for i, row := range rows {
+		data := map[string]interface{}{}
+		for k, v := range row["group"].(map[string]interface{}) {
+			data[k] = v
+		}
+		for k, v := range row["values"].(map[string]interface{}) {
+			data[k] = v
+		}
+		flat[i] = Document{Id: strconv.Itoa(i), Collection: "rows", Data: data}
+	}
+	ordered := fakeQuery(flat, map[string]interface{}{"table": "rows", "sort": q["sort"], "skip": q["skip"], "limit": q["limit"]})
+	out := make([]map[string]interface{}, len(ordered))
+	for i, o := range ordered {
+		idx, _ := strconv.Atoi(o["id"].(string))
+		out[i] = rows[idx]
+	}
+	return out
+}
+
+// fakeGroup returns the group by values of a document, nil for no document
+func fakeGroup(doc map[string]interface{}, groupBy []interface{}) map[string]interface{} {
+	group := map[string]interface{}{}
+	for _, g := range groupBy {
+		if doc != nil {
+			group[g.(string)] = fakeField(doc, g.(string))
+		}
+	}
+	return group
+}
+
+// fakeQuery evaluates a structured query against docs
+func fakeQuery(docs []Document, q map[string]interface{}) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, doc := range docs {
+		if q["table"] != doc.Collection || !fakeMatches(doc, q["filter"]) {
+			continue
+		}
+		out = append(out, map[string]interface{}{
+			"id":         doc.Id,
+			"collection": doc.Collection,
+			"data":       doc.Data,
+		})
+	}
+	if sorts, ok := q["sort"].([]interface{}); ok {
+		sort.SliceStable(out, func(i, j int) bool {
+			for _, sp := range sorts {
+				spec := sp.(map[string]interface{})
+				field := spec["field"].(string)
+				a, b := fakeField(out[i], field), fakeField(out[j], field)
+				if fakeCompare(a, b) == 0 {
+					continue
+				}
+				if spec["direction"] == "desc" {
+					return fakeCompare(a, b) > 0
+				}
+				return fakeCompare(a, b) < 0
+			}
+			return false
+		})
+	}
+	if skip, ok := q["skip"].(float64); ok {
+		if int(skip) >= len(out) {
+			out = nil
+		} else {
+			out = out[int(skip):]
+		}
+	}
+	if limit, ok := q["limit"].(float64); ok && int(limit) < len(out) {
+		out = out[:int(limit)]
+	}
+	return out
+}
+
+func fakeField(doc map[string]interface{}, field string) interface{} {
+	if field == "id" {
+		return doc["id"]
+	}
+	d := &Document{Data: doc["data"].(map[string]interface{})}
+	v, _ := d.Get(field)
+	return v
+}
+
+func fakeCompare(a, b interface{}) int {
+	if fa, ok := toFloat(a); ok {
+		fb, _ := toFloat(b)
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	if ba, ok := a.(bool); ok {
+		if bb, ok := b.(bool); ok && ba == bb {
+			return 0
+		}
+		return 1
+	}
+	sa, _ := a.(string)
+	sb, _ := b.(string)
+	return strings.Compare(sa, sb)
+}
+
+func fakeMatches(doc Document, filter interface{}) bool {
+	conds, _ := filter.(map[string]interface{})
+	wire := map[string]interface{}{"id": doc.Id, "data": doc.Data}
+	for field, ops := range conds {
+		if field == "$or" || field == "$and" {
+			list := ops.(map[string]interface{})[field].([]interface{})
+			matched := false
+			for _, c := range list {
+				cond := c.(map[string]interface{})
+				ok := fakeMatches(doc, map[string]interface{}{
+					cond["field"].(string): map[string]interface{}{cond["operator"].(string): cond["value"]},
+				})
+				if field == "$and" && !ok {
+					return false
+				}
+				matched = matched || ok
+			}
+			if field == "$or" && !matched {
+				return false
+			}
+			continue
+		}
+		v := fakeField(wire, field)
+		for op, want := range ops.(map[string]interface{}) {
+			var ok bool
+			switch op {
+			case "$eq":
+				ok = fakeCompare(v, want) == 0 && v != nil
+			case "$ne":
+				ok = fakeCompare(v, want) != 0
+			case "$gt":
+				ok = v != nil && fakeCompare(v, want) > 0
+			case "$gte":
+				ok = v != nil && fakeCompare(v, want) >= 0
+			case "$lt":
+				ok = v != nil && fakeCompare(v, want) < 0
+			case "$lte":
+				ok = v != nil && fakeCompare(v, want) <= 0
+			case "$in":
+				for _, w := range want.([]interface{}) {
+					ok = ok || fakeCompare(v, w) == 0
+				}
+			}
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// fakeStore is an in-memory versioned document store with optimistic
+// transactions. A commit fails with a serialization failure if a document
+// the transaction read or wrote was changed by someone else since.
+type fakeStore struct {
+	mu      sync.Mutex
+	records map[string]*fakeRecord
+	txs     map[string]*fakeTx
+	nextID  int
+}
+
+type fakeRecord struct {
+	doc     Document
+	version int
+}
+
+type fakeTx struct {
+	// seen holds the version of every document touched, 0 if it did not exist
+	seen map[string]int
+	// writes holds pending document states; nil deletes the document
+	writes map[string]*Document
+}
+
+// serveStore answers Begin, Commit, Rollback, Query, Insert, Update and
+// Delete requests from a fakeStore holding docs
+func (s *fakeServer) serveStore(docs ...Document) *fakeStore {
+	st := &fakeStore{records: map[string]*fakeRecord{}, txs: map[string]*fakeTx{}}
+	for _, doc := range docs {
+		st.records[doc.Collection+"/"+doc.Id] = &fakeRecord{doc: doc, version: 1}
+	}
+	s.handle("Begin", st.begin)
+	s.handle("Commit", st.commit)
+	s.handle("Rollback", st.rollback)
+	s.handle("Query", st.query)
+	for _, msgType := range []string{"Insert", "Update", "Delete"} {
+		s.handle(msgType, st.write)
+	}
+	return st
+}
+
+// get returns the committed state of a document
+func (st *fakeStore) get(collection, id string) (Document, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	rec, ok := st.records[collection+"/"+id]
+	if !ok {
+		return Document{}, false
+	}
+	return rec.doc, true
+}
+
+func (st *fakeStore) begin(msg map[string]interface{}) map[string]interface{} {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.nextID++
+	id := "tx-" + strconv.Itoa(st.nextID)
+	st.txs[id] = &fakeTx{seen: map[string]int{}, writes: map[string]*Document{}}
+	return map[string]interface{}{"type": "Result", "transaction_id": id}
+}
+
+// lookup returns the transaction of msg, nil if msg is outside a transaction
+func (st *fakeStore) lookup(msg map[string]interface{}) (*fakeTx, map[string]interface{}) {
+	id, _ := msg["transaction_id"].(string)
+	if id == "" {
+		return nil, nil
+	}
+	tx, ok := st.txs[id]
+	if !ok {
+		return nil, map[string]interface{}{"type": "Error", "code": CodeNotFound, "message": "unknown transaction " + id}
+	}
+	return tx, nil
+}
+
+func (st *fakeStore) commit(msg map[string]interface{}) map[string]interface{} {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	tx, errReply := st.lookup(msg)
+	if errReply != nil {
+		return errReply
+	}
+	if tx == nil {
+		return map[string]interface{}{"type": "Error", "code": CodeValidation, "message": "missing transaction_id"}
+	}
+	delete(st.txs, msg["transaction_id"].(string))
+	for key, version := range tx.seen {
+		current := 0
+		if rec, ok := st.records[key]; ok {
+			current = rec.version
+		}
+		if current != version {
+			return map[string]interface{}{"type": "Error", "code": CodeSerializationFailure, "message": "concurrent update of " + key, "retryable": true}
+		}
+	}
+	for key, doc := range tx.writes {
+		st.apply(key, doc)
+	}
+	return map[string]interface{}{"type": "Result"}
+}
+
+func (st *fakeStore) rollback(msg map[string]interface{}) map[string]interface{} {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	tx, errReply := st.lookup(msg)
+	if errReply != nil {
+		return errReply
+	}
+	if tx == nil {
+		return map[string]interface{}{"type": "Error", "code": CodeValidation, "message": "missing transaction_id"}
+	}
+	delete(st.txs, msg["transaction_id"].(string))
+	return map[string]interface{}{"type": "Result"}
+}
+
+// apply commits a document state; the caller must hold st.mu
+func (st *fakeStore) apply(key string, doc *Document) {
+	rec, ok := st.records[key]
+	switch {
+	case doc == nil:
+		delete(st.records, key)
+	case ok:
+		rec.version++
+		rec.doc = *doc
+	default:
+		st.records[key] = &fakeRecord{doc: *doc, version: 1}
+	}
+}
+
+// current returns the state of a document as seen by tx, which may be nil
+func (st *fakeStore) current(tx *fakeTx, key string) (Document, bool) {
+	if tx != nil {
+		if doc, ok := tx.writes[key]; ok {
+			if doc == nil {
+				return Document{}, false
+			}
+			return *doc, true
+		}
+	}
+	rec, ok := st.records[key]
+	if !ok {
+		return Document{}, false
+	}
+	return rec.doc, true
+}
+
+// revision returns the committed version of a document as seen by tx, or 0
+// while tx has a pending write to it
+func (st *fakeStore) revision(tx *fakeTx, key string) int {
+	if tx != nil {
+		if _, ok := tx.writes[key]; ok {
+			return 0
+		}
+	}
+	if rec, ok := st.records[key]; ok {
+		return rec.version
+	}
+	return 0
+}
+
+// touch records the version of a document the first time tx sees it
+func (st *fakeStore) touch(tx *fakeTx, key string) {
+	if tx == nil {
+		return
+	}
+	if _, ok := tx.seen[key]; ok {
+		return
+	}
+	if rec, ok := st.records[key]; ok {
+		tx.seen[key] = rec.version
+	} else {
+		tx.seen[key] = 0
+	}
+}
+
+func (st *fakeStore) query(msg map[string]interface{}) map[string]interface{} {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	tx, errReply := st.lookup(msg)
+	if errReply != nil {
+		return errReply
+	}
+	keys := make([]string, 0, len(st.records))
+	for key := range st.records {
+		keys = append(keys, key)
+	}
+	if tx != nil {
+		for key := range tx.writes {
+			if _, ok := st.records[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	var docs []Document
+	for _, key := range keys {
+		if doc, ok := st.current(tx, key); ok {
+			docs = append(docs, doc)
+		}
+	}
+	q, _ := msg["structured_query"].(map[string]interface{})
+	out := fakeQuery(docs, q)
+	for _, doc := range out {
+		key := doc["collection"].(string) + "/" + doc["id"].(string)
+		st.touch(tx, key)
+		doc["revision"] = st.revision(tx, key)
+	}
+	return documentsReply(out...)
+}
+
+func (st *fakeStore) write(msg map[string]interface{}) map[string]interface{} {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	tx, errReply := st.lookup(msg)
+	if errReply != nil {
+		return errReply
+	}
+	collection, _ := msg["collection"].(string)
+	id, _ := msg["document_id"].(string)
+	data, _ := msg["data"].(map[string]interface{})
+	if msg["type"] == "Insert" {
+		st.nextID++
+		id = "doc-" + strconv.Itoa(st.nextID)
+	}
+	key := collection + "/" + id
+
+	if ifMatch, ok := msg["if_match"].(map[string]interface{}); ok {
+		current, _ := st.current(tx, key)
+		rev, _ := ifMatch["revision"].(float64)
+		if rev != 0 && int(rev) != st.revision(tx, key) ||
+			ifMatch["updated_at"] != nil && ifMatch["updated_at"] != current.UpdatedAt {
+			return map[string]interface{}{"type": "Error", "code": CodeConflict, "message": key + " was modified"}
+		}
+	}
+
+	var next *Document
+	switch msg["type"] {
+	case "Insert":
+		next = &Document{Id: id, Collection: collection, Data: data}
+	case "Update":
+		if _, ok := st.current(tx, key); !ok {
+			return map[string]interface{}{"type": "Error", "code": CodeNotFound, "message": key + " not found"}
+		}
+		next = &Document{Id: id, Collection: collection, Data: data}
+	case "Delete":
+		if _, ok := st.current(tx, key); !ok {
+			return map[string]interface{}{"type": "Error", "code": CodeNotFound, "message": key + " not found"}
+		}
+	}
+
+	if tx != nil {
+		st.touch(tx, key)
+		tx.writes[key] = next
+	} else {
+		st.apply(key, next)
+	}
+	reply := map[string]interface{}{"id": id, "collection": collection}
+	if next != nil {
+		reply["data"] = next.Data
+		reply["revision"] = st.revision(tx, key)
+	}
+	return documentsReply(reply)
+}
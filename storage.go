@@ -6,6 +6,8 @@ package squirreldb
 
 import (
 	"bytes"
+	"context"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,49 +23,134 @@ type StorageOptions struct {
 	AccessKey string
 	SecretKey string
 	Region    string
+	// Breaker fails requests fast while the server is unhealthy; nil disables it
+	Breaker *CircuitBreaker
+	// RateLimits throttles requests per operation type; nil disables limiting
+	RateLimits *StorageRateLimits
 }
 
 // Storage is an S3-compatible storage client
 type Storage struct {
-	endpoint  string
-	accessKey string
-	secretKey string
-	region    string
-	client    *http.Client
+	endpoint   string
+	accessKey  string
+	secretKey  string
+	region     string
+	client     *http.Client
+	breaker    *CircuitBreaker
+	rateLimits *StorageRateLimits
+	ctx        context.Context
 }
 
 // StorageError represents a storage error
 type StorageError struct {
 	StatusCode int
-	Message    string
+	// Code is the S3 error code, e.g. NoSuchKey or AccessDenied
+	Code      string
+	Message   string
+	Resource  string
+	RequestID string
 }
 
 func (e *StorageError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("storage error %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
 	return fmt.Sprintf("storage error %d: %s", e.StatusCode, e.Message)
 }
 
+// Is matches the error category sentinels (ErrNotFound, ErrConflict, ...)
+func (e *StorageError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Code == "NoSuchKey" || e.Code == "NoSuchBucket" || e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.Code == "BucketAlreadyExists" || e.Code == "BucketAlreadyOwnedByYou" ||
+			e.Code == "BucketNotEmpty" || e.StatusCode == http.StatusConflict
+	case ErrPermissionDenied:
+		return e.Code == "AccessDenied" || e.StatusCode == http.StatusForbidden
+	case ErrValidation:
+		return e.Code == "InvalidArgument" || e.Code == "InvalidBucketName" || e.Code == "EntityTooLarge"
+	}
+	return false
+}
+
+// Retryable reports whether the request may succeed if retried
+func (e *StorageError) Retryable() bool {
+	switch e.Code {
+	case "SlowDown", "RequestTimeout", "InternalError", "ServiceUnavailable":
+		return true
+	}
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// parseStorageError builds a StorageError from an S3 XML error body
+func parseStorageError(statusCode int, body []byte) *StorageError {
+	var xmlErr struct {
+		Code      string `xml:"Code"`
+		Message   string `xml:"Message"`
+		Resource  string `xml:"Resource"`
+		RequestID string `xml:"RequestId"`
+	}
+	if err := xml.Unmarshal(body, &xmlErr); err != nil || xmlErr.Code == "" {
+		return &StorageError{StatusCode: statusCode, Message: string(body)}
+	}
+	return &StorageError{
+		StatusCode: statusCode,
+		Code:       xmlErr.Code,
+		Message:    xmlErr.Message,
+		Resource:   xmlErr.Resource,
+		RequestID:  xmlErr.RequestID,
+	}
+}
+
 // ConnectStorage creates a new storage client
 func ConnectStorage(opts *StorageOptions) *Storage {
 	if opts.Region == "" {
 		opts.Region = "us-east-1"
 	}
 	return &Storage{
-		endpoint:  strings.TrimSuffix(opts.Endpoint, "/"),
-		accessKey: opts.AccessKey,
-		secretKey: opts.SecretKey,
-		region:    opts.Region,
-		client:    &http.Client{Timeout: 30 * time.Second},
+		endpoint:   strings.TrimSuffix(opts.Endpoint, "/"),
+		accessKey:  opts.AccessKey,
+		secretKey:  opts.SecretKey,
+		region:     opts.Region,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		breaker:    opts.Breaker,
+		rateLimits: opts.RateLimits,
+		ctx:        context.Background(),
 	}
 }
 
+// WithContext returns a view of the storage client whose requests are
+// bound to ctx, both while waiting for a rate limit token and in flight
+func (s *Storage) WithContext(ctx context.Context) *Storage {
+	view := *s
+	view.ctx = ctx
+	return &view
+}
+
 func (s *Storage) request(method, path string, body []byte, headers map[string]string) ([]byte, http.Header, error) {
+	if err := s.rateLimits.forMethod(method).acquire(s.ctx); err != nil {
+		return nil, nil, err
+	}
+
+	var respBody []byte
+	var respHeaders http.Header
+	err := s.breaker.Execute(func() error {
+		var err error
+		respBody, respHeaders, err = s.roundTrip(method, path, body, headers)
+		return err
+	})
+	return respBody, respHeaders, err
+}
+
+func (s *Storage) roundTrip(method, path string, body []byte, headers map[string]string) ([]byte, http.Header, error) {
 	url := s.endpoint + path
 	var bodyReader io.Reader
 	if body != nil {
 		bodyReader = bytes.NewReader(body)
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(s.ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -83,7 +170,7 @@ func (s *Storage) request(method, path string, body []byte, headers map[string]s
 
 	respBody, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode >= 400 {
-		return nil, nil, &StorageError{StatusCode: resp.StatusCode, Message: string(respBody)}
+		return nil, nil, parseStorageError(resp.StatusCode, respBody)
 	}
 
 	return respBody, resp.Header, nil
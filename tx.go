@@ -0,0 +1,205 @@
+// SquirrelDB Go SDK - Transactions
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrTxDone is returned by operations on a transaction that has already
+// been committed or rolled back
+var ErrTxDone = errors.New("transaction already committed or rolled back")
+
+// txBackoff spaces out reruns of transactions aborted by a conflict
+var txBackoff = RetryPolicy{
+	InitialBackoff: 10 * time.Millisecond,
+	MaxBackoff:     500 * time.Millisecond,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// Tx is a transaction started by Client.Transaction. Its writes become
+// visible to others atomically on commit.
+type Tx struct {
+	client *Client
+	id     string
+	done   atomic.Bool
+}
+
+// ID returns the server-assigned transaction id
+func (tx *Tx) ID() string {
+	return tx.id
+}
+
+// Transaction runs fn in a transaction. It is committed if fn returns nil
+// and rolled back if fn returns an error or panics. If the transaction is
+// aborted by a serialization conflict with a concurrent write, fn is run
+// again in a new transaction, up to Options.TransactionAttempts times, so
+// it must not have side effects outside the transaction.
+func (c *Client) Transaction(ctx context.Context, fn func(tx *Tx) error) error {
+	for attempt := 1; ; attempt++ {
+		err := c.runTransaction(ctx, fn)
+		if err == nil || attempt >= c.txAttempts || !isSerializationFailure(err) {
+			return err
+		}
+
+		if err := txWait(ctx, attempt, err); err != nil {
+			return err
+		}
+	}
+}
+
+// txWait sleeps before rerunning a conflicted attempt. If ctx ends first it
+// returns the context error joined with err, the attempt's error.
+func txWait(ctx context.Context, attempt int, err error) error {
+	timer := time.NewTimer(txBackoff.backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return errors.Join(ctx.Err(), err)
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (c *Client) runTransaction(ctx context.Context, fn func(tx *Tx) error) error {
+	result, err := c.request(ctx, map[string]interface{}{"type": "Begin"})
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		TransactionID string `json:"transaction_id"`
+	}
+	json.Unmarshal(result, &resp)
+	if resp.TransactionID == "" {
+		// Operations without an id would silently run outside the transaction
+		return errors.New("transaction: server returned no transaction id")
+	}
+	tx := &Tx{client: c, id: resp.TransactionID}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.rollback(ctx)
+			panic(p)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		tx.rollback(ctx)
+		return err
+	}
+	return tx.finish(ctx, "Commit")
+}
+
+// rollback aborts the transaction, even if ctx is already done
+func (tx *Tx) rollback(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	tx.finish(ctx, "Rollback")
+}
+
+func (tx *Tx) finish(ctx context.Context, msgType string) error {
+	if !tx.done.CompareAndSwap(false, true) {
+		return ErrTxDone
+	}
+	_, err := tx.client.send(ctx, map[string]interface{}{"type": msgType, "transaction_id": tx.id})
+	return err
+}
+
+func isSerializationFailure(err error) bool {
+	var serverErr *ServerError
+	return errors.As(err, &serverErr) && serverErr.Code == CodeSerializationFailure
+}
+
+// send issues a request within the transaction. Requests are not retried,
+// since the transaction is aborted by the first failure.
+func (tx *Tx) send(ctx context.Context, msg map[string]interface{}) (json.RawMessage, error) {
+	if tx.done.Load() {
+		return nil, ErrTxDone
+	}
+	msg["transaction_id"] = tx.id
+	return tx.client.send(ctx, msg)
+}
+
+// Find executes a query built with QueryBuilder within the transaction
+func (tx *Tx) Find(ctx context.Context, q *QueryBuilder) ([]Document, error) {
+	result, err := tx.send(ctx, map[string]interface{}{
+		"type":             "Query",
+		"structured_query": q.CompileStructured(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct{ Documents []Document }
+	json.Unmarshal(result, &resp)
+	return resp.Documents, nil
+}
+
+// Get a document by id within the transaction, returning a *NotFoundError
+// if it does not exist
+func (tx *Tx) Get(ctx context.Context, collection, id string) (*Document, error) {
+	docs, err := tx.Find(ctx, Table(collection).Find(Field("id").Eq(id)).Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, &NotFoundError{Collection: collection, ID: id}
+	}
+	return &docs[0], nil
+}
+
+func (tx *Tx) mutate(ctx context.Context, msg map[string]interface{}) (*Document, error) {
+	if err := tx.client.validateMessage(msg); err != nil {
+		return nil, err
+	}
+	result, err := tx.send(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	return firstDocument(result), nil
+}
+
+// Insert a document within the transaction
+func (tx *Tx) Insert(ctx context.Context, collection string, data map[string]interface{}) (*Document, error) {
+	return tx.mutate(ctx, map[string]interface{}{
+		"type":       "Insert",
+		"collection": collection,
+		"data":       data,
+	})
+}
+
+// Update a document within the transaction
+func (tx *Tx) Update(ctx context.Context, collection, id string, data map[string]interface{}) (*Document, error) {
+	return tx.mutate(ctx, map[string]interface{}{
+		"type":        "Update",
+		"collection":  collection,
+		"document_id": id,
+		"data":        data,
+	})
+}
+
+// UpdateWith applies update operators to a document within the transaction
+func (tx *Tx) UpdateWith(ctx context.Context, collection, id string, update *UpdateBuilder) (*Document, error) {
+	ops, err := update.Compile()
+	if err != nil {
+		return nil, err
+	}
+	return tx.mutate(ctx, map[string]interface{}{
+		"type":        "Update",
+		"collection":  collection,
+		"document_id": id,
+		"update":      ops,
+	})
+}
+
+// Delete a document within the transaction
+func (tx *Tx) Delete(ctx context.Context, collection, id string) (*Document, error) {
+	return tx.mutate(ctx, map[string]interface{}{
+		"type":        "Delete",
+		"collection":  collection,
+		"document_id": id,
+	})
+}
@@ -0,0 +1,215 @@
+// SquirrelDB Go SDK - Transaction Tests
+
+package squirreldb
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func fakeAccounts() []Document {
+	return []Document{
+		{Id: "alice", Collection: "accounts", Data: map[string]interface{}{"credits": 100.0}},
+		{Id: "bob", Collection: "accounts", Data: map[string]interface{}{"credits": 10.0}},
+	}
+}
+
+// transfer moves credits between two accounts within tx
+func transfer(ctx context.Context, tx *Tx, from, to string, amount float64) error {
+	src, err := tx.Get(ctx, "accounts", from)
+	if err != nil {
+		return err
+	}
+	dst, err := tx.Get(ctx, "accounts", to)
+	if err != nil {
+		return err
+	}
+	balance := src.FloatOr("credits", 0)
+	if balance < amount {
+		return errors.New("insufficient credits")
+	}
+	if _, err := tx.Update(ctx, "accounts", from, map[string]interface{}{"credits": balance - amount}); err != nil {
+		return err
+	}
+	_, err = tx.Update(ctx, "accounts", to, map[string]interface{}{"credits": dst.FloatOr("credits", 0) + amount})
+	return err
+}
+
+func credits(t *testing.T, st *fakeStore, id string) interface{} {
+	t.Helper()
+	doc, ok := st.get("accounts", id)
+	if !ok {
+		t.Fatalf("Expected account %s to exist", id)
+	}
+	return doc.Data["credits"]
+}
+
+func TestTransactionCommits(t *testing.T) {
+	srv := newFakeServer(t)
+	st := srv.serveStore(fakeAccounts()...)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	err := client.Transaction(ctx, func(tx *Tx) error {
+		if err := transfer(ctx, tx, "alice", "bob", 30); err != nil {
+			return err
+		}
+		// Writes are not visible outside the transaction before commit
+		if got := credits(t, st, "alice"); got != 100.0 {
+			t.Errorf("Expected uncommitted balance 100, got %v", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+	if credits(t, st, "alice") != 70.0 || credits(t, st, "bob") != 40.0 {
+		t.Errorf("Unexpected balances: alice %v, bob %v", credits(t, st, "alice"), credits(t, st, "bob"))
+	}
+	if n := len(srv.messages("Commit")); n != 1 {
+		t.Errorf("Expected 1 Commit, got %d", n)
+	}
+}
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	srv := newFakeServer(t)
+	st := srv.serveStore(fakeAccounts()...)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	var tx *Tx
+	err := client.Transaction(ctx, func(current *Tx) error {
+		tx = current
+		return transfer(ctx, current, "bob", "alice", 50)
+	})
+	if err == nil || err.Error() != "insufficient credits" {
+		t.Fatalf("Expected insufficient credits, got %v", err)
+	}
+	if n := len(srv.messages("Rollback")); n != 1 {
+		t.Errorf("Expected 1 Rollback, got %d", n)
+	}
+	if len(srv.messages("Commit")) != 0 {
+		t.Error("Expected no Commit")
+	}
+	if credits(t, st, "bob") != 10.0 {
+		t.Errorf("Expected bob's balance to be unchanged, got %v", credits(t, st, "bob"))
+	}
+	if _, err := tx.Get(ctx, "accounts", "bob"); !errors.Is(err, ErrTxDone) {
+		t.Errorf("Expected ErrTxDone after rollback, got %v", err)
+	}
+}
+
+func TestTransactionRollsBackOnPanic(t *testing.T) {
+	srv := newFakeServer(t)
+	st := srv.serveStore(fakeAccounts()...)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("Expected panic to propagate, got %v", p)
+			}
+		}()
+		client.Transaction(ctx, func(tx *Tx) error {
+			tx.Update(ctx, "accounts", "alice", map[string]interface{}{"credits": 0.0})
+			panic("boom")
+		})
+	}()
+
+	if n := len(srv.messages("Rollback")); n != 1 {
+		t.Errorf("Expected 1 Rollback, got %d", n)
+	}
+	if credits(t, st, "alice") != 100.0 {
+		t.Errorf("Expected alice's balance to be unchanged, got %v", credits(t, st, "alice"))
+	}
+}
+
+func TestTransactionRetriesSerializationFailure(t *testing.T) {
+	srv := newFakeServer(t)
+	st := srv.serveStore(fakeAccounts()...)
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	attempts := 0
+	err := client.Transaction(ctx, func(tx *Tx) error {
+		attempts++
+		if err := transfer(ctx, tx, "alice", "bob", 30); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			// A concurrent writer changes alice's balance before the commit
+			_, err := client.Update(ctx, "accounts", "alice", map[string]interface{}{"credits": 50.0})
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+	if credits(t, st, "alice") != 20.0 || credits(t, st, "bob") != 40.0 {
+		t.Errorf("Unexpected balances: alice %v, bob %v", credits(t, st, "alice"), credits(t, st, "bob"))
+	}
+}
+
+func TestTransactionGivesUpAfterAttempts(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.serveStore(fakeAccounts()...)
+	client := srv.connect(&Options{TransactionAttempts: 2})
+	ctx := context.Background()
+
+	attempts := 0
+	err := client.Transaction(ctx, func(tx *Tx) error {
+		attempts++
+		if _, err := tx.Get(ctx, "accounts", "alice"); err != nil {
+			return err
+		}
+		_, err := client.Update(ctx, "accounts", "alice", map[string]interface{}{"credits": float64(attempts)})
+		return err
+	})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected conflict error, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestTransactionStopsWhenContextEnds(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.serveStore(fakeAccounts()...)
+	client := srv.connect(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	conflict := &ServerError{Code: CodeSerializationFailure, Message: "concurrent update"}
+	attempts := 0
+	err := client.Transaction(ctx, func(tx *Tx) error {
+		attempts++
+		cancel()
+		return conflict
+	})
+	if attempts != 1 || !errors.Is(err, context.Canceled) || !errors.Is(err, conflict) {
+		t.Errorf("Expected cancellation joined with the conflict after one attempt, got %d (err=%v)", attempts, err)
+	}
+}
+
+func TestTransactionRequiresID(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Begin", func(msg map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "Result"}
+	})
+	client := srv.connect(nil)
+
+	called := false
+	err := client.Transaction(context.Background(), func(tx *Tx) error {
+		called = true
+		return nil
+	})
+	if err == nil || called {
+		t.Errorf("Expected Begin without a transaction id to fail before running fn, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+// SquirrelDB Go SDK - Types v2
+
+// Package typesv2 is the next version of the SquirrelDB data types, with
+// parsed timestamps and a typed ChangeEvent.Old.
+//
+// Values use the same JSON and MessagePack wire format as their squirreldb
+// counterparts, so code can migrate one call site at a time: convert with
+// FromDocument/FromChangeEvent and back with V1. The encoding is equivalent
+// rather than byte-identical: timestamps are re-formatted as RFC 3339 with
+// nanoseconds, and decoding fails if a timestamp is not RFC 3339.
+package typesv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	squirreldb "github.com/squirreldb/squirreldb-sdk-go"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Document - A document stored in SquirrelDB
+type Document struct {
+	Id         string
+	Collection string
+	Data       map[string]interface{}
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ChangeEvent - A change event from a subscription
+type ChangeEvent struct {
+	Type     squirreldb.ChangeType
+	Document *Document
+	// Old is the previous version of the document, when the server sends one
+	Old *Document
+	// OldValue holds the raw old value when it is not a document
+	OldValue interface{}
+	New      *Document
+}
+
+// FromDocument converts a v1 document, parsing its timestamps
+func FromDocument(d squirreldb.Document) (Document, error) {
+	created, err := d.CreatedTime()
+	if err != nil {
+		return Document{}, err
+	}
+	updated, err := d.UpdatedTime()
+	if err != nil {
+		return Document{}, err
+	}
+	return Document{
+		Id:         d.Id,
+		Collection: d.Collection,
+		Data:       d.Data,
+		CreatedAt:  created,
+		UpdatedAt:  updated,
+	}, nil
+}
+
+// V1 converts the document back to the v1 type
+func (d Document) V1() squirreldb.Document {
+	return squirreldb.Document{
+		Id:         d.Id,
+		Collection: d.Collection,
+		Data:       d.Data,
+		CreatedAt:  formatTime(d.CreatedAt),
+		UpdatedAt:  formatTime(d.UpdatedAt),
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// MarshalJSON encodes the document in the v1 wire format
+func (d Document) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.V1())
+}
+
+// UnmarshalJSON decodes a document from the v1 wire format
+func (d *Document) UnmarshalJSON(data []byte) error {
+	var v1 squirreldb.Document
+	if err := json.Unmarshal(data, &v1); err != nil {
+		return err
+	}
+	doc, err := FromDocument(v1)
+	if err != nil {
+		return err
+	}
+	*d = doc
+	return nil
+}
+
+// EncodeMsgpack encodes the document in the v1 wire format
+func (d Document) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(d.V1())
+}
+
+// DecodeMsgpack decodes a document from the v1 wire format
+func (d *Document) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var v1 squirreldb.Document
+	if err := dec.Decode(&v1); err != nil {
+		return err
+	}
+	doc, err := FromDocument(v1)
+	if err != nil {
+		return err
+	}
+	*d = doc
+	return nil
+}
+
+// FromChangeEvent converts a v1 change event, decoding Old into a
+// Document when it is one and keeping it in OldValue otherwise
+func FromChangeEvent(e squirreldb.ChangeEvent) (ChangeEvent, error) {
+	out := ChangeEvent{Type: e.Type}
+	var err error
+	if out.Document, err = convertDocument(e.Document); err != nil {
+		return ChangeEvent{}, fmt.Errorf("document: %w", err)
+	}
+	if out.New, err = convertDocument(e.New); err != nil {
+		return ChangeEvent{}, fmt.Errorf("new: %w", err)
+	}
+	if old, oldErr := e.OldDocument(); oldErr == nil {
+		if out.Old, err = convertDocument(old); err != nil {
+			return ChangeEvent{}, fmt.Errorf("old: %w", err)
+		}
+	} else {
+		out.OldValue = *e.Old
+	}
+	return out, nil
+}
+
+func convertDocument(d *squirreldb.Document) (*Document, error) {
+	if d == nil {
+		return nil, nil
+	}
+	doc, err := FromDocument(*d)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// V1 converts the event back to the v1 type
+func (e ChangeEvent) V1() squirreldb.ChangeEvent {
+	out := squirreldb.ChangeEvent{Type: e.Type}
+	if e.Document != nil {
+		doc := e.Document.V1()
+		out.Document = &doc
+	}
+	if e.New != nil {
+		doc := e.New.V1()
+		out.New = &doc
+	}
+	if e.Old != nil {
+		// Old is untyped in v1; use the map form a server would send
+		var old interface{}
+		raw, _ := json.Marshal(e.Old.V1())
+		json.Unmarshal(raw, &old)
+		out.Old = &old
+	} else if e.OldValue != nil {
+		old := e.OldValue
+		out.Old = &old
+	}
+	return out
+}
+
+// MarshalJSON encodes the event in the v1 wire format
+func (e ChangeEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.V1())
+}
+
+// UnmarshalJSON decodes an event from the v1 wire format
+func (e *ChangeEvent) UnmarshalJSON(data []byte) error {
+	var v1 squirreldb.ChangeEvent
+	if err := json.Unmarshal(data, &v1); err != nil {
+		return err
+	}
+	event, err := FromChangeEvent(v1)
+	if err != nil {
+		return err
+	}
+	*e = event
+	return nil
+}
+
+// EncodeMsgpack encodes the event in the v1 wire format
+func (e ChangeEvent) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(e.V1())
+}
+
+// DecodeMsgpack decodes an event from the v1 wire format
+func (e *ChangeEvent) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var v1 squirreldb.ChangeEvent
+	if err := dec.Decode(&v1); err != nil {
+		return err
+	}
+	event, err := FromChangeEvent(v1)
+	if err != nil {
+		return err
+	}
+	*e = event
+	return nil
+}
@@ -0,0 +1,107 @@
+// SquirrelDB Go SDK - Types v2 Tests
+
+package typesv2
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	squirreldb "github.com/squirreldb/squirreldb-sdk-go"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const updateEventJSON = `{
+	"type": "update",
+	"document": null,
+	"old": {"id": "u1", "collection": "users", "data": {"name": "Alice"}, "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-01T00:00:00Z"},
+	"new": {"id": "u1", "collection": "users", "data": {"name": "Alicia"}, "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-02T00:00:00Z"}
+}`
+
+func TestChangeEventFromJSON(t *testing.T) {
+	var event ChangeEvent
+	if err := json.Unmarshal([]byte(updateEventJSON), &event); err != nil {
+		t.Fatalf("Failed to unmarshal change event: %v", err)
+	}
+
+	if event.Type != squirreldb.ChangeTypeUpdate {
+		t.Errorf("Expected type 'update', got '%s'", event.Type)
+	}
+	if event.Old == nil || event.Old.Data["name"] != "Alice" {
+		t.Fatalf("Expected old document, got %+v", event.Old)
+	}
+	if !event.New.UpdatedAt.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected parsed updated_at, got %v", event.New.UpdatedAt)
+	}
+}
+
+func TestChangeEventJSONMatchesV1(t *testing.T) {
+	var v1 squirreldb.ChangeEvent
+	var v2 ChangeEvent
+	json.Unmarshal([]byte(updateEventJSON), &v1)
+	json.Unmarshal([]byte(updateEventJSON), &v2)
+
+	want, _ := json.Marshal(v1)
+	got, err := json.Marshal(v2)
+	if err != nil {
+		t.Fatalf("Failed to marshal v2 event: %v", err)
+	}
+	var wantMap, gotMap map[string]interface{}
+	json.Unmarshal(want, &wantMap)
+	json.Unmarshal(got, &gotMap)
+	if !reflect.DeepEqual(wantMap, gotMap) {
+		t.Errorf("JSON differs from v1:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestDocumentMsgpackMatchesV1(t *testing.T) {
+	v1 := squirreldb.Document{
+		Id:         "u1",
+		Collection: "users",
+		Data:       map[string]interface{}{"name": "Alice"},
+		CreatedAt:  "2024-01-01T00:00:00Z",
+		UpdatedAt:  "2024-01-02T00:00:00Z",
+	}
+	doc, err := FromDocument(v1)
+	if err != nil {
+		t.Fatalf("FromDocument failed: %v", err)
+	}
+
+	want, _ := msgpack.Marshal(v1)
+	got, err := msgpack.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to marshal v2 document: %v", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("MessagePack differs from v1")
+	}
+
+	var decoded Document
+	if err := msgpack.Unmarshal(got, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal v2 document: %v", err)
+	}
+	if decoded.Id != "u1" || !decoded.CreatedAt.Equal(doc.CreatedAt) {
+		t.Errorf("Unexpected decoded document: %+v", decoded)
+	}
+}
+
+func TestChangeEventNonDocumentOld(t *testing.T) {
+	var old interface{} = "tombstone"
+	event, err := FromChangeEvent(squirreldb.ChangeEvent{Type: squirreldb.ChangeTypeDelete, Old: &old})
+	if err != nil {
+		t.Fatalf("FromChangeEvent failed: %v", err)
+	}
+	if event.Old != nil || event.OldValue != "tombstone" {
+		t.Errorf("Expected raw OldValue, got %+v", event)
+	}
+	if back := event.V1(); back.Old == nil || *back.Old != "tombstone" {
+		t.Errorf("Expected OldValue to round trip, got %+v", back.Old)
+	}
+}
+
+func TestFromDocumentInvalidTimestamp(t *testing.T) {
+	if _, err := FromDocument(squirreldb.Document{CreatedAt: "yesterday"}); err == nil {
+		t.Error("Expected error for invalid created_at")
+	}
+}
@@ -0,0 +1,160 @@
+// SquirrelDB Go SDK - Update Builder
+
+package squirreldb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UpdateDocument represents the wire format update, keyed by operator then field
+type UpdateDocument map[string]map[string]interface{}
+
+// UpdateBuilder builds field-level updates, so concurrent writers touching
+// different fields do not overwrite each other
+type UpdateBuilder struct {
+	ops    UpdateDocument
+	fields map[string]string
+	err    error
+}
+
+// NewUpdate creates an empty update builder
+func NewUpdate() *UpdateBuilder {
+	return &UpdateBuilder{ops: make(UpdateDocument), fields: make(map[string]string)}
+}
+
+func (u *UpdateBuilder) add(operator, field string, value interface{}) *UpdateBuilder {
+	if prev, ok := u.fields[field]; ok && u.err == nil {
+		u.err = fmt.Errorf("update: field %s used by both %s and %s", field, prev, operator)
+	}
+	u.fields[field] = operator
+	if _, ok := u.ops[operator]; !ok {
+		u.ops[operator] = make(map[string]interface{})
+	}
+	u.ops[operator][field] = value
+	return u
+}
+
+// Set a field to a value
+func (u *UpdateBuilder) Set(field string, value interface{}) *UpdateBuilder {
+	return u.add("$set", field, value)
+}
+
+// Unset removes fields
+func (u *UpdateBuilder) Unset(fields ...string) *UpdateBuilder {
+	for _, f := range fields {
+		u.add("$unset", f, true)
+	}
+	return u
+}
+
+// Inc increments a numeric field, treating a missing field as 0
+func (u *UpdateBuilder) Inc(field string, amount interface{}) *UpdateBuilder {
+	return u.add("$inc", field, amount)
+}
+
+// Mul multiplies a numeric field, treating a missing field as 0
+func (u *UpdateBuilder) Mul(field string, factor interface{}) *UpdateBuilder {
+	return u.add("$mul", field, factor)
+}
+
+// Push appends values to an array field
+func (u *UpdateBuilder) Push(field string, values ...interface{}) *UpdateBuilder {
+	return u.add("$push", field, eachValue(values))
+}
+
+// AddToSet appends values to an array field unless already present
+func (u *UpdateBuilder) AddToSet(field string, values ...interface{}) *UpdateBuilder {
+	return u.add("$addToSet", field, eachValue(values))
+}
+
+// Pull removes all array elements equal to value
+func (u *UpdateBuilder) Pull(field string, value interface{}) *UpdateBuilder {
+	return u.add("$pull", field, value)
+}
+
+// PullWhere removes all array elements matching the conditions, whose
+// fields are relative to the element, e.g. PullWhere("items", Field("qty").Lte(0))
+func (u *UpdateBuilder) PullWhere(field string, conditions ...FilterCondition) *UpdateBuilder {
+	return u.add("$pull", field, Table("").Find(conditions...).buildFilterObject())
+}
+
+// Rename a field
+func (u *UpdateBuilder) Rename(field, newName string) *UpdateBuilder {
+	if _, ok := u.fields[newName]; ok && u.err == nil {
+		u.err = fmt.Errorf("update: field %s used by both %s and $rename", newName, u.fields[newName])
+	}
+	u.fields[newName] = "$rename"
+	return u.add("$rename", field, newName)
+}
+
+func eachValue(values []interface{}) interface{} {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return map[string]interface{}{"$each": values}
+}
+
+// Compile returns a copy of the update document, or an error if the same
+// field is targeted by more than one operator or the update is empty
+func (u *UpdateBuilder) Compile() (UpdateDocument, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+	if len(u.ops) == 0 {
+		return nil, fmt.Errorf("update: no operations")
+	}
+	if err := u.checkOverlap(); err != nil {
+		return nil, err
+	}
+	// Copy so that changing the result does not change the builder
+	out := make(UpdateDocument, len(u.ops))
+	for op, fields := range u.ops {
+		out[op] = make(map[string]interface{}, len(fields))
+		for f, v := range fields {
+			out[op][f] = v
+		}
+	}
+	return out, nil
+}
+
+// checkOverlap rejects updates touching a field and one of its parents
+func (u *UpdateBuilder) checkOverlap() error {
+	fields := make([]string, 0, len(u.fields))
+	for f := range u.fields {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	for _, f := range fields {
+		for i := strings.IndexByte(f, '.'); i >= 0; i = nextDot(f, i) {
+			if _, ok := u.fields[f[:i]]; ok {
+				return fmt.Errorf("update: fields %s and %s overlap", f[:i], f)
+			}
+		}
+	}
+	return nil
+}
+
+// nextDot returns the index of the next '.' in s after i, or -1
+func nextDot(s string, i int) int {
+	if j := strings.IndexByte(s[i+1:], '.'); j >= 0 {
+		return i + 1 + j
+	}
+	return -1
+}
+
+// UpdateWith applies field-level update operators to a document
+func (c *Client) UpdateWith(ctx context.Context, collection, id string, update *UpdateBuilder) (*Document, error) {
+	ops, err := update.Compile()
+	if err != nil {
+		return nil, err
+	}
+	return c.mutate(ctx, map[string]interface{}{
+		"type":        "Update",
+		"collection":  collection,
+		"document_id": id,
+		"update":      ops,
+	})
+}
@@ -0,0 +1,109 @@
+// SquirrelDB Go SDK - Update Builder Tests
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestUpdateBuilderCompile(t *testing.T) {
+	ops, err := NewUpdate().
+		Set("profile.name", "Alice").
+		Unset("legacy", "tmp").
+		Inc("visits", 1).
+		Mul("score", 1.5).
+		Push("tags", "a", "b").
+		AddToSet("roles", "admin").
+		PullWhere("items", Field("qty").Lte(0)).
+		Rename("nick", "nickname").
+		Compile()
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	raw, _ := json.Marshal(ops)
+	expected := `{"$addToSet":{"roles":"admin"},"$inc":{"visits":1},"$mul":{"score":1.5},` +
+		`"$pull":{"items":{"qty":{"$lte":0}}},"$push":{"tags":{"$each":["a","b"]}},` +
+		`"$rename":{"nick":"nickname"},"$set":{"profile.name":"Alice"},"$unset":{"legacy":true,"tmp":true}}`
+	if string(raw) != expected {
+		t.Errorf("Unexpected update:\n got %s\nwant %s", raw, expected)
+	}
+}
+
+func TestUpdateBuilderCompileReturnsCopy(t *testing.T) {
+	u := NewUpdate().Set("name", "Ann")
+	doc, _ := u.Compile()
+	doc["$set"]["name"] = "Bob"
+	doc["$inc"] = map[string]interface{}{"visits": 1}
+
+	again, _ := u.Compile()
+	if again["$set"]["name"] != "Ann" || again["$inc"] != nil {
+		t.Errorf("Expected the builder to be unaffected by changes to a result, got %v", again)
+	}
+}
+
+func TestUpdateBuilderRejectsConflicts(t *testing.T) {
+	cases := map[string]*UpdateBuilder{
+		"empty":      NewUpdate(),
+		"same field": NewUpdate().Set("count", 1).Inc("count", 1),
+		"overlap":    NewUpdate().Set("address", map[string]interface{}{}).Set("address.city", "Paris"),
+		"rename":     NewUpdate().Set("nickname", "a").Rename("nick", "nickname"),
+		// a-b sorts between a and a.c, which must still be compared
+		"overlap with sibling": NewUpdate().Set("a", 1).Set("a-b", 2).Set("a.c", 3),
+		"deep overlap":         NewUpdate().Unset("a").Inc("a.b.c", 1),
+	}
+	for name, u := range cases {
+		if _, err := u.Compile(); err == nil {
+			t.Errorf("%s: expected compile error", name)
+		}
+	}
+}
+
+func TestUpdateBuilderAllowsSiblingPrefixes(t *testing.T) {
+	if _, err := NewUpdate().Set("a", 1).Set("a-b", 2).Set("ab.c", 3).Compile(); err != nil {
+		t.Errorf("Expected fields sharing a name prefix not to overlap, got %v", err)
+	}
+}
+
+func TestClientUpdateWith(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Update", func(msg map[string]interface{}) map[string]interface{} {
+		return documentsReply(map[string]interface{}{
+			"id": msg["document_id"], "collection": msg["collection"], "data": map[string]interface{}{"visits": 2},
+		})
+	})
+	client := srv.connect(nil)
+
+	doc, err := client.UpdateWith(context.Background(), "users", "u1", NewUpdate().Inc("visits", 1))
+	if err != nil {
+		t.Fatalf("UpdateWith failed: %v", err)
+	}
+	if doc.Id != "u1" {
+		t.Errorf("Expected document u1, got %+v", doc)
+	}
+
+	msgs := srv.messages("Update")
+	if len(msgs) != 1 {
+		t.Fatalf("Expected 1 Update message, got %d", len(msgs))
+	}
+	update, _ := msgs[0]["update"].(map[string]interface{})
+	inc, _ := update["$inc"].(map[string]interface{})
+	if inc["visits"] != float64(1) {
+		t.Errorf("Expected $inc on visits, got %v", msgs[0]["update"])
+	}
+	if _, hasData := msgs[0]["data"]; hasData {
+		t.Error("Expected no data key on operator update")
+	}
+	if msgs[0]["idempotency_key"] == nil {
+		t.Error("Expected idempotency key on operator update")
+	}
+
+	if _, err := client.UpdateWith(context.Background(), "users", "u1", NewUpdate()); err == nil {
+		t.Error("Expected error for empty update")
+	}
+	if n := len(srv.messages("Update")); n != 1 {
+		t.Errorf("Expected invalid update not to be sent, got %d messages", n)
+	}
+}
@@ -0,0 +1,69 @@
+// SquirrelDB Go SDK - Upsert and Replace
+
+package squirreldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// WriteResult is the outcome of a write that may create or modify a document
+type WriteResult struct {
+	Document *Document
+	// Created is true if no matching document existed and one was inserted
+	Created bool
+	// Modified is true if an existing document was changed
+	Modified bool
+}
+
+func parseWriteResult(result json.RawMessage) (*WriteResult, error) {
+	var resp struct {
+		Documents []Document
+		Created   bool
+		Modified  bool
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, err
+	}
+	wr := &WriteResult{Created: resp.Created, Modified: resp.Modified}
+	if len(resp.Documents) > 0 {
+		wr.Document = &resp.Documents[0]
+	}
+	return wr, nil
+}
+
+// Upsert updates the document matching filter with data, or inserts data
+// if none matches. Combine several conditions with And, e.g. to match on
+// a natural key spanning more than one field. The filter must match at
+// most one document.
+func (c *Client) Upsert(ctx context.Context, collection string, filter FilterCondition, data map[string]interface{}) (*WriteResult, error) {
+	if filter.Field == "" {
+		return nil, errors.New("upsert: empty filter")
+	}
+	result, err := c.write(ctx, map[string]interface{}{
+		"type":       "Upsert",
+		"collection": collection,
+		"filter":     Table(collection).Find(filter).CompileStructured().Filter,
+		"data":       data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseWriteResult(result)
+}
+
+// Replace overwrites the whole document with the given id, creating it if
+// it does not exist. Unlike Update, fields missing from data are removed.
+func (c *Client) Replace(ctx context.Context, collection, id string, data map[string]interface{}) (*WriteResult, error) {
+	result, err := c.write(ctx, map[string]interface{}{
+		"type":        "Replace",
+		"collection":  collection,
+		"document_id": id,
+		"data":        data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseWriteResult(result)
+}
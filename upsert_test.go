@@ -0,0 +1,77 @@
+// SquirrelDB Go SDK - Upsert and Replace Tests
+
+package squirreldb
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClientUpsert(t *testing.T) {
+	srv := newFakeServer(t)
+	docs := []Document{{Id: "u1", Collection: "users", Data: map[string]interface{}{"email": "a@example.com", "name": "Alice"}}}
+	srv.handle("Upsert", func(msg map[string]interface{}) map[string]interface{} {
+		data, _ := msg["data"].(map[string]interface{})
+		for i, doc := range docs {
+			if fakeMatches(doc, msg["filter"]) {
+				docs[i].Data = data
+				reply := documentsReply(map[string]interface{}{"id": doc.Id, "collection": "users", "data": data})
+				reply["modified"] = true
+				return reply
+			}
+		}
+		docs = append(docs, Document{Id: "u2", Collection: "users", Data: data})
+		reply := documentsReply(map[string]interface{}{"id": "u2", "collection": "users", "data": data})
+		reply["created"] = true
+		return reply
+	})
+	client := srv.connect(nil)
+	ctx := context.Background()
+
+	res, err := client.Upsert(ctx, "users", Field("email").Eq("a@example.com"), map[string]interface{}{"email": "a@example.com", "name": "Alicia"})
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if res.Created || !res.Modified || res.Document.Id != "u1" {
+		t.Errorf("Expected u1 to be modified, got %+v", res)
+	}
+
+	res, err = client.Upsert(ctx, "users", Field("email").Eq("b@example.com"), map[string]interface{}{"email": "b@example.com"})
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if !res.Created || res.Modified || res.Document.Id != "u2" {
+		t.Errorf("Expected u2 to be created, got %+v", res)
+	}
+
+	msgs := srv.messages("Upsert")
+	filter, _ := msgs[0]["filter"].(map[string]interface{})
+	if _, ok := filter["email"]; !ok || msgs[0]["idempotency_key"] == nil {
+		t.Errorf("Unexpected upsert message: %v", msgs[0])
+	}
+
+	if _, err := client.Upsert(ctx, "users", FilterCondition{}, nil); err == nil {
+		t.Error("Expected error for empty filter")
+	}
+}
+
+func TestClientReplace(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.handle("Replace", func(msg map[string]interface{}) map[string]interface{} {
+		reply := documentsReply(map[string]interface{}{"id": msg["document_id"], "collection": msg["collection"], "data": msg["data"]})
+		reply["modified"] = true
+		return reply
+	})
+	client := srv.connect(nil)
+
+	res, err := client.Replace(context.Background(), "users", "u1", map[string]interface{}{"name": "Bob"})
+	if err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if !res.Modified || res.Created || res.Document.Data["name"] != "Bob" {
+		t.Errorf("Unexpected result: %+v", res)
+	}
+	if msgs := srv.messages("Replace"); len(msgs) != 1 || msgs[0]["document_id"] != "u1" {
+		t.Errorf("Unexpected replace messages: %v", msgs)
+	}
+}